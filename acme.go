@@ -0,0 +1,225 @@
+package govultr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	acmeChallengeLabel = "_acme-challenge"
+	defaultACMETTL     = 120
+	dnsPort            = "53"
+	defaultACMEWait    = 5 * time.Second
+	defaultACMETimeout = 5 * time.Minute
+)
+
+// ACMEChallengeValue returns the TXT record value for an ACME DNS-01 challenge
+// given the key authorization from the ACME server
+func ACMEChallengeValue(keyAuth string) string {
+	sum := sha256.Sum256([]byte(keyAuth))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// DNS01Solver publishes ACME DNS-01 challenges as TXT records on Vultr DNS
+type DNS01Solver struct {
+	client *Client
+
+	// TTL of the challenge records, defaults to 120 seconds
+	TTL int
+
+	// Nameservers overrides the nameservers queried while waiting for propagation.
+	// Entries are host or host:port. By default the zone's NS records are used.
+	Nameservers []string
+
+	// Wait controls how often and how long propagation is polled
+	Wait *WaitOptions
+
+	mu      sync.Mutex
+	records map[string]string
+}
+
+// NewDNS01Solver returns a DNS-01 solver that manages records through client
+func NewDNS01Solver(client *Client) *DNS01Solver {
+	return &DNS01Solver{
+		client:  client,
+		TTL:     defaultACMETTL,
+		Wait:    &WaitOptions{Interval: defaultACMEWait, Timeout: defaultACMETimeout},
+		records: make(map[string]string),
+	}
+}
+
+// Present creates the _acme-challenge TXT record for fqdn in the longest matching
+// zone on the account
+func (s *DNS01Solver) Present(ctx context.Context, fqdn, keyAuth string) error {
+	zone, name, err := s.challengeName(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	value := ACMEChallengeValue(keyAuth)
+	record, _, err := s.client.DomainRecord.Create(ctx, zone, &DomainRecordCreateReq{
+		Name: name,
+		Type: RecordTypeTXT,
+		Data: quoteTXT([]string{value}),
+		TTL:  s.TTL,
+	})
+	if err != nil {
+		return fmt.Errorf("creating challenge record for %s: %w", fqdn, err)
+	}
+
+	s.mu.Lock()
+	s.records[challengeKey(fqdn, value)] = record.ID
+	s.mu.Unlock()
+
+	return nil
+}
+
+// WaitForPropagation polls the zone's authoritative nameservers until every one of
+// them serves the challenge value
+func (s *DNS01Solver) WaitForPropagation(ctx context.Context, fqdn, keyAuth string) error {
+	zone, _, err := s.challengeName(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	nameservers, err := s.nameservers(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	name := acmeChallengeLabel + "." + strings.TrimSuffix(fqdn, ".") + "."
+	value := ACMEChallengeValue(keyAuth)
+
+	return waitFor(ctx, s.Wait, fmt.Sprintf("%s to propagate", name), func(ctx context.Context) (bool, error) {
+		for _, ns := range nameservers {
+			// lookup failures are expected until the record propagates
+			values, _ := lookupTXTAt(ctx, ns, name)
+			if !containsString(values, value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// CleanUp removes the challenge record created by Present
+func (s *DNS01Solver) CleanUp(ctx context.Context, fqdn, keyAuth string) error {
+	zone, name, err := s.challengeName(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	value := ACMEChallengeValue(keyAuth)
+	key := challengeKey(fqdn, value)
+
+	s.mu.Lock()
+	recordID, ok := s.records[key]
+	s.mu.Unlock()
+
+	if !ok {
+		// Present may have run in another process so look the record up
+		records, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+			return s.client.DomainRecord.List(ctx, zone, options)
+		})
+		if err != nil {
+			return err
+		}
+
+		for idx := range records {
+			r := &records[idx]
+			if r.Type == RecordTypeTXT && strings.EqualFold(r.Name, name) && strings.Trim(r.Data, `"`) == value {
+				recordID = r.ID
+				break
+			}
+		}
+
+		if recordID == "" {
+			return nil
+		}
+	}
+
+	if err := s.client.DomainRecord.Delete(ctx, zone, recordID); err != nil {
+		return fmt.Errorf("deleting challenge record for %s: %w", fqdn, err)
+	}
+
+	s.mu.Lock()
+	delete(s.records, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func challengeKey(fqdn, value string) string {
+	return strings.ToLower(strings.TrimSuffix(fqdn, ".")) + "\x00" + value
+}
+
+// challengeName finds the zone hosting fqdn and the record name of its challenge within that zone
+func (s *DNS01Solver) challengeName(ctx context.Context, fqdn string) (zone, name string, err error) {
+	zone, err = s.client.Domain.FindZone(ctx, fqdn)
+	if err != nil {
+		return "", "", err
+	}
+
+	relative, _ := zoneRelativeName(fqdn, zone)
+	if relative == "" {
+		return zone, acmeChallengeLabel, nil
+	}
+	return zone, acmeChallengeLabel + "." + relative, nil
+}
+
+func (s *DNS01Solver) nameservers(ctx context.Context, zone string) ([]string, error) {
+	if len(s.Nameservers) > 0 {
+		return s.Nameservers, nil
+	}
+
+	records, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return s.client.DomainRecord.List(ctx, zone, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var nameservers []string
+	for idx := range records {
+		if records[idx].Type == RecordTypeNS && records[idx].Name == "" {
+			nameservers = append(nameservers, records[idx].Data)
+		}
+	}
+
+	if len(nameservers) == 0 {
+		ns, err := net.DefaultResolver.LookupNS(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range ns {
+			nameservers = append(nameservers, n.Host)
+		}
+	}
+
+	return nameservers, nil
+}
+
+// lookupTXTAt queries a single nameserver directly, bypassing caching resolvers
+func lookupTXTAt(ctx context.Context, nameserver, name string) ([]string, error) {
+	addr := nameserver
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		addr = net.JoinHostPort(strings.TrimSuffix(nameserver, "."), dnsPort)
+	}
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+
+	return resolver.LookupTXT(ctx, name)
+}
@@ -0,0 +1,279 @@
+package govultr
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDNSServer is a minimal authoritative nameserver that answers TXT queries
+type testDNSServer struct {
+	conn net.PacketConn
+
+	mu  sync.Mutex
+	txt map[string][]string
+}
+
+func newTestDNSServer(t *testing.T) *testDNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp listener unavailable: %v", err)
+	}
+
+	s := &testDNSServer{conn: conn, txt: make(map[string][]string)}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+
+	return s
+}
+
+func (s *testDNSServer) addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *testDNSServer) set(name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txt[strings.ToLower(name)] = values
+}
+
+func (s *testDNSServer) serve() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.answer(buf[:n]); resp != nil {
+			_, _ = s.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+func (s *testDNSServer) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// walk the question name
+	var labels []string
+	off := 12
+	for off < len(query) && query[off] != 0 {
+		l := int(query[off])
+		if off+1+l > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[off+1:off+1+l]))
+		off += 1 + l
+	}
+	off++
+	if off+4 > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[off:])
+	question := query[12 : off+4]
+	name := strings.ToLower(strings.Join(labels, ".")) + "."
+
+	s.mu.Lock()
+	values := s.txt[name]
+	s.mu.Unlock()
+	if qtype != 16 {
+		values = nil
+	}
+
+	resp := make([]byte, 12, 512)
+	copy(resp, query[:2])
+	binary.BigEndian.PutUint16(resp[2:], 0x8400)
+	binary.BigEndian.PutUint16(resp[4:], 1)
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(values)))
+	resp = append(resp, question...)
+
+	for _, v := range values {
+		resp = append(resp, 0xc0, 0x0c, 0, 16, 0, 1, 0, 0, 0, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(v)+1))
+		resp = append(resp, byte(len(v)))
+		resp = append(resp, v...)
+	}
+
+	return resp
+}
+
+func TestACMEChallengeValue(t *testing.T) {
+	// RFC 8555 digest of the key authorization, base64url without padding
+	got := ACMEChallengeValue("token.thumbprint")
+	if len(got) != 43 || strings.ContainsAny(got, "+/=") {
+		t.Errorf("ACMEChallengeValue returned %q, expected 43 url-safe characters", got)
+	}
+
+	if got != ACMEChallengeValue("token.thumbprint") {
+		t.Error("ACMEChallengeValue is not deterministic")
+	}
+}
+
+func TestDomainServiceHandler_FindZone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domains":[{"domain":"example.com"},{"domain":"sub.example.com"},{"domain":"ample.com"}],"meta":{"total":3,"links":{}}}`)
+	})
+
+	tests := map[string]string{
+		"example.com":          "example.com",
+		"www.example.com.":     "example.com",
+		"WWW.Sub.Example.com":  "sub.example.com",
+		"a.b.sub.example.com.": "sub.example.com",
+	}
+
+	for fqdn, expected := range tests {
+		zone, err := client.Domain.FindZone(ctx, fqdn)
+		if err != nil {
+			t.Errorf("Domain.FindZone(%q) returned %+v", fqdn, err)
+			continue
+		}
+		if zone != expected {
+			t.Errorf("Domain.FindZone(%q) returned %q, expected %q", fqdn, zone, expected)
+		}
+	}
+
+	if _, err := client.Domain.FindZone(ctx, "example.org"); err == nil {
+		t.Error("Domain.FindZone expected an error for an unknown zone")
+	}
+}
+
+func TestDNS01Solver(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dns := newTestDNSServer(t)
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domains":[{"domain":"example.com"},{"domain":"sub.example.com"}],"meta":{"total":2,"links":{}}}`)
+	})
+
+	var (
+		mu      sync.Mutex
+		created *DomainRecordCreateReq
+		deleted string
+	)
+
+	mux.HandleFunc("/v2/domains/sub.example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodPost)
+		}
+
+		var req DomainRecordCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+
+		mu.Lock()
+		created = &req
+		mu.Unlock()
+
+		fmt.Fprintf(writer, `{"record":{"id":"challenge","type":"TXT","name":%q,"data":%q,"ttl":%d}}`, req.Name, req.Data, req.TTL)
+	})
+
+	mux.HandleFunc("/v2/domains/sub.example.com/records/challenge", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodDelete)
+		}
+
+		mu.Lock()
+		deleted = "challenge"
+		mu.Unlock()
+	})
+
+	solver := NewDNS01Solver(client)
+	solver.Nameservers = []string{dns.addr()}
+	solver.Wait = &WaitOptions{Interval: 10 * time.Millisecond, Timeout: 2 * time.Second}
+
+	const keyAuth = "token.thumbprint"
+	value := ACMEChallengeValue(keyAuth)
+
+	if err := solver.Present(ctx, "www.sub.example.com", keyAuth); err != nil {
+		t.Fatalf("DNS01Solver.Present returned %+v", err)
+	}
+
+	expected := &DomainRecordCreateReq{Name: "_acme-challenge.www", Type: "TXT", Data: `"` + value + `"`, TTL: 120}
+	if created == nil || *created != *expected {
+		t.Fatalf("DNS01Solver.Present created %+v, expected %+v", created, expected)
+	}
+
+	// the record becomes visible on the nameserver after a few polls
+	time.AfterFunc(50*time.Millisecond, func() {
+		dns.set("_acme-challenge.www.sub.example.com.", "stale", value)
+	})
+
+	if err := solver.WaitForPropagation(ctx, "www.sub.example.com", keyAuth); err != nil {
+		t.Fatalf("DNS01Solver.WaitForPropagation returned %+v", err)
+	}
+
+	if err := solver.CleanUp(ctx, "www.sub.example.com", keyAuth); err != nil {
+		t.Fatalf("DNS01Solver.CleanUp returned %+v", err)
+	}
+
+	if deleted != "challenge" {
+		t.Errorf("DNS01Solver.CleanUp did not delete the challenge record")
+	}
+}
+
+func TestDNS01Solver_WaitForPropagationTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dns := newTestDNSServer(t)
+	dns.set("_acme-challenge.example.com.", "something-else")
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domains":[{"domain":"example.com"}],"meta":{"total":1,"links":{}}}`)
+	})
+
+	solver := NewDNS01Solver(client)
+	solver.Nameservers = []string{dns.addr()}
+	solver.Wait = &WaitOptions{Interval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond}
+
+	if err := solver.WaitForPropagation(ctx, "example.com", "token.thumbprint"); err == nil {
+		t.Error("DNS01Solver.WaitForPropagation expected a timeout error")
+	}
+}
+
+func TestDNS01Solver_CleanUpLookup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	value := ACMEChallengeValue("token.thumbprint")
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domains":[{"domain":"example.com"}],"meta":{"total":1,"links":{}}}`)
+	})
+
+	mux.HandleFunc("/v2/domains/example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprintf(writer, `{"records":[
+			{"id":"other","type":"TXT","name":"_acme-challenge","data":"\"other\""},
+			{"id":"mine","type":"TXT","name":"_acme-challenge","data":"\"%s\""}
+		],"meta":{"total":2,"links":{}}}`, value)
+	})
+
+	deleted := false
+	mux.HandleFunc("/v2/domains/example.com/records/mine", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodDelete)
+		}
+		deleted = true
+	})
+
+	if err := NewDNS01Solver(client).CleanUp(ctx, "example.com.", "token.thumbprint"); err != nil {
+		t.Fatalf("DNS01Solver.CleanUp returned %+v", err)
+	}
+
+	if !deleted {
+		t.Error("DNS01Solver.CleanUp did not delete the record found by lookup")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -29,6 +30,11 @@ type Backup struct {
 	Status      string `json:"status"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (b *Backup) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(b.DateCreated)
+}
+
 type backupsBase struct {
 	Backups []Backup `json:"backups"`
 	Meta    *Meta    `json:"meta"`
@@ -0,0 +1,227 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Backup schedule types accepted by SetBackupSchedule
+const (
+	BackupScheduleDaily        = "daily"
+	BackupScheduleWeekly       = "weekly"
+	BackupScheduleMonthly      = "monthly"
+	BackupScheduleDailyAltEven = "daily_alt_even"
+	BackupScheduleDailyAltOdd  = "daily_alt_odd"
+)
+
+const (
+	backupStatusComplete = "complete"
+	maxBackupHour        = 23
+	maxBackupDow         = 7
+	maxBackupDom         = 28
+)
+
+// ErrNoBackups is returned when an instance has no completed backups to restore from
+var ErrNoBackups = errors.New("no completed backups found")
+
+// BackupPolicy is a named backup schedule applied to every instance carrying Tag.
+// Hour is in UTC. Dow (1 = Sunday through 7 = Saturday) is only used by weekly
+// policies and Dom (1-28) only by monthly policies.
+type BackupPolicy struct {
+	Name string
+	Tag  string
+	Type string
+	Hour int
+	Dow  int
+	Dom  int
+}
+
+// BackupPolicyDrift describes an instance whose backup schedule differs from its policy
+type BackupPolicyDrift struct {
+	InstanceID string
+	Label      string
+	Policy     string
+	Current    *BackupSchedule
+}
+
+// BackupPolicyReport is the result of comparing instance backup schedules with their policies
+type BackupPolicyReport struct {
+	// Compliant holds the IDs of instances whose schedule matches their policy
+	Compliant []string
+	// Drifted holds instances that have backups enabled with a schedule that differs from their policy
+	Drifted []BackupPolicyDrift
+	// Disabled holds instances covered by a policy that do not have backups enabled
+	Disabled []BackupPolicyDrift
+}
+
+// Validate checks that the policy describes a schedule the API will accept
+func (b *BackupPolicy) Validate() error {
+	if b.Tag == "" {
+		return fmt.Errorf("backup policy %q: tag is required", b.Name)
+	}
+
+	if b.Hour < 0 || b.Hour > maxBackupHour {
+		return fmt.Errorf("backup policy %q: hour must be between 0 and %d", b.Name, maxBackupHour)
+	}
+
+	switch b.Type {
+	case BackupScheduleDaily, BackupScheduleDailyAltEven, BackupScheduleDailyAltOdd:
+	case BackupScheduleWeekly:
+		if b.Dow < 1 || b.Dow > maxBackupDow {
+			return fmt.Errorf("backup policy %q: dow must be between 1 and %d", b.Name, maxBackupDow)
+		}
+	case BackupScheduleMonthly:
+		if b.Dom < 1 || b.Dom > maxBackupDom {
+			return fmt.Errorf("backup policy %q: dom must be between 1 and %d", b.Name, maxBackupDom)
+		}
+	default:
+		return fmt.Errorf("backup policy %q: unknown schedule type %q", b.Name, b.Type)
+	}
+
+	return nil
+}
+
+func (b *BackupPolicy) scheduleReq() *BackupScheduleReq {
+	req := &BackupScheduleReq{Type: b.Type, Hour: IntToIntPtr(b.Hour)}
+
+	switch b.Type {
+	case BackupScheduleWeekly:
+		req.Dow = IntToIntPtr(b.Dow)
+	case BackupScheduleMonthly:
+		req.Dom = b.Dom
+	}
+
+	return req
+}
+
+func (b *BackupPolicy) matches(schedule *BackupSchedule) bool {
+	if schedule.Type != b.Type || schedule.Hour != b.Hour {
+		return false
+	}
+
+	switch b.Type {
+	case BackupScheduleWeekly:
+		return schedule.Dow == b.Dow
+	case BackupScheduleMonthly:
+		return schedule.Dom == b.Dom
+	}
+
+	return true
+}
+
+func taggedInstances(ctx context.Context, client *Client, tag string) ([]Instance, error) {
+	return listAll(func(options *ListOptions) ([]Instance, *Meta, *http.Response, error) {
+		options.Tag = tag
+		return client.Instance.List(ctx, options)
+	})
+}
+
+// ApplyBackupPolicy sets the policy's backup schedule on every instance with the policy tag
+// and returns the IDs of the instances that were updated.
+func ApplyBackupPolicy(ctx context.Context, client *Client, policy *BackupPolicy) ([]string, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	instances, err := taggedInstances(ctx, client, policy.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []string
+	for idx := range instances {
+		if _, err := client.Instance.SetBackupSchedule(ctx, instances[idx].ID, policy.scheduleReq()); err != nil {
+			return updated, fmt.Errorf("setting backup schedule on instance %s: %w", instances[idx].ID, err)
+		}
+		updated = append(updated, instances[idx].ID)
+	}
+
+	return updated, nil
+}
+
+// ReportBackupPolicies compares the backup schedule of every instance covered by the policies
+// with the policy that applies to it. When an instance matches several policies the first one
+// wins.
+func ReportBackupPolicies(ctx context.Context, client *Client, policies []BackupPolicy) (*BackupPolicyReport, error) {
+	report := &BackupPolicyReport{}
+	seen := make(map[string]bool)
+
+	for idx := range policies {
+		policy := &policies[idx]
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+
+		instances, err := taggedInstances(ctx, client, policy.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range instances {
+			instance := &instances[i]
+			if seen[instance.ID] {
+				continue
+			}
+			seen[instance.ID] = true
+
+			schedule, _, err := client.Instance.GetBackupSchedule(ctx, instance.ID)
+			if err != nil {
+				return nil, fmt.Errorf("getting backup schedule for instance %s: %w", instance.ID, err)
+			}
+
+			drift := BackupPolicyDrift{InstanceID: instance.ID, Label: instance.Label, Policy: policy.Name, Current: schedule}
+			switch {
+			case schedule.Enabled == nil || !*schedule.Enabled:
+				report.Disabled = append(report.Disabled, drift)
+			case !policy.matches(schedule):
+				report.Drifted = append(report.Drifted, drift)
+			default:
+				report.Compliant = append(report.Compliant, instance.ID)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// RestoreLatestBackup restores an instance from its most recent completed backup and returns
+// the backup that was used. Backups without a readable creation date are skipped.
+func RestoreLatestBackup(ctx context.Context, client *Client, instanceID string) (*Backup, error) {
+	backups, err := listAll(func(options *ListOptions) ([]Backup, *Meta, *http.Response, error) {
+		options.InstanceID = instanceID
+		return client.Backup.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *Backup
+	var latestTime time.Time
+	for idx := range backups {
+		if backups[idx].Status != backupStatusComplete {
+			continue
+		}
+
+		created, err := backups[idx].CreatedAt()
+		if err != nil || created.IsZero() {
+			continue
+		}
+
+		if latest == nil || created.After(latestTime) {
+			latest, latestTime = &backups[idx], created
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("instance %s: %w", instanceID, ErrNoBackups)
+	}
+
+	if _, err := client.Instance.Restore(ctx, instanceID, &RestoreReq{BackupID: latest.ID}); err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
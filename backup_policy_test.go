@@ -0,0 +1,179 @@
+package govultr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBackupPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy BackupPolicy
+		valid  bool
+	}{
+		{name: "daily", policy: BackupPolicy{Tag: "web", Type: BackupScheduleDaily, Hour: 3}, valid: true},
+		{name: "weekly", policy: BackupPolicy{Tag: "web", Type: BackupScheduleWeekly, Hour: 3, Dow: 2}, valid: true},
+		{name: "monthly", policy: BackupPolicy{Tag: "web", Type: BackupScheduleMonthly, Dom: 28}, valid: true},
+		{name: "missing tag", policy: BackupPolicy{Type: BackupScheduleDaily}},
+		{name: "bad hour", policy: BackupPolicy{Tag: "web", Type: BackupScheduleDaily, Hour: 24}},
+		{name: "weekly without dow", policy: BackupPolicy{Tag: "web", Type: BackupScheduleWeekly}},
+		{name: "monthly bad dom", policy: BackupPolicy{Tag: "web", Type: BackupScheduleMonthly, Dom: 31}},
+		{name: "unknown type", policy: BackupPolicy{Tag: "web", Type: "hourly"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("BackupPolicy.Validate returned %+v, expected nil", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("BackupPolicy.Validate returned nil, expected an error")
+			}
+		})
+	}
+}
+
+func TestApplyBackupPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/instances", func(writer http.ResponseWriter, request *http.Request) {
+		if tag := request.URL.Query().Get("tag"); tag != "web" {
+			t.Errorf("ApplyBackupPolicy listed instances with tag %q, expected web", tag)
+		}
+		fmt.Fprint(writer, `{"instances":[{"id":"a"},{"id":"b"}],"meta":{"total":2,"links":{"next":"","prev":""}}}`)
+	})
+
+	var mu sync.Mutex
+	received := make(map[string]BackupScheduleReq)
+	handler := func(id string) func(http.ResponseWriter, *http.Request) {
+		return func(writer http.ResponseWriter, request *http.Request) {
+			var req BackupScheduleReq
+			if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			received[id] = req
+			mu.Unlock()
+		}
+	}
+	mux.HandleFunc("/v2/instances/a/backup-schedule", handler("a"))
+	mux.HandleFunc("/v2/instances/b/backup-schedule", handler("b"))
+
+	policy := &BackupPolicy{Name: "weekly-web", Tag: "web", Type: BackupScheduleWeekly, Hour: 4, Dow: 1}
+	updated, err := ApplyBackupPolicy(ctx, client, policy)
+	if err != nil {
+		t.Fatalf("ApplyBackupPolicy returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(updated, []string{"a", "b"}) {
+		t.Errorf("ApplyBackupPolicy returned %+v, expected %+v", updated, []string{"a", "b"})
+	}
+
+	expected := BackupScheduleReq{Type: "weekly", Hour: IntToIntPtr(4), Dow: IntToIntPtr(1)}
+	for _, id := range []string{"a", "b"} {
+		if !reflect.DeepEqual(received[id], expected) {
+			t.Errorf("ApplyBackupPolicy sent %+v to %s, expected %+v", received[id], id, expected)
+		}
+	}
+}
+
+func TestReportBackupPolicies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/instances", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Query().Get("tag") {
+		case "web":
+			fmt.Fprint(writer, `{"instances":[{"id":"a","label":"web-a"},{"id":"b","label":"web-b"}],"meta":{"total":2,"links":{}}}`)
+		case "db":
+			fmt.Fprint(writer, `{"instances":[{"id":"b","label":"web-b"},{"id":"c","label":"db-c"}],"meta":{"total":2,"links":{}}}`)
+		}
+	})
+
+	mux.HandleFunc("/v2/instances/a/backup-schedule", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"backup_schedule":{"enabled":true,"type":"daily","hour":3}}`)
+	})
+	mux.HandleFunc("/v2/instances/b/backup-schedule", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"backup_schedule":{"enabled":true,"type":"weekly","hour":3,"dow":2}}`)
+	})
+	mux.HandleFunc("/v2/instances/c/backup-schedule", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"backup_schedule":{"enabled":false}}`)
+	})
+
+	policies := []BackupPolicy{
+		{Name: "daily-web", Tag: "web", Type: BackupScheduleDaily, Hour: 3},
+		{Name: "monthly-db", Tag: "db", Type: BackupScheduleMonthly, Hour: 1, Dom: 1},
+	}
+
+	report, err := ReportBackupPolicies(ctx, client, policies)
+	if err != nil {
+		t.Fatalf("ReportBackupPolicies returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(report.Compliant, []string{"a"}) {
+		t.Errorf("ReportBackupPolicies compliant returned %+v, expected [a]", report.Compliant)
+	}
+
+	if len(report.Drifted) != 1 || report.Drifted[0].InstanceID != "b" || report.Drifted[0].Policy != "daily-web" {
+		t.Errorf("ReportBackupPolicies drifted returned %+v", report.Drifted)
+	}
+
+	if len(report.Disabled) != 1 || report.Disabled[0].InstanceID != "c" || report.Disabled[0].Label != "db-c" {
+		t.Errorf("ReportBackupPolicies disabled returned %+v", report.Disabled)
+	}
+}
+
+func TestRestoreLatestBackup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/backups", func(writer http.ResponseWriter, request *http.Request) {
+		if id := request.URL.Query().Get("instance_id"); id != "a" {
+			t.Errorf("RestoreLatestBackup listed backups for %q, expected a", id)
+		}
+		fmt.Fprint(writer, `{"backups":[
+			{"id":"old","date_created":"2024-01-01 00:00:00","status":"complete"},
+			{"id":"new","date_created":"2024-03-01 00:00:00","status":"complete"},
+			{"id":"undated","date_created":"","status":"complete"},
+			{"id":"garbled","date_created":"03/01/2024","status":"complete"},
+			{"id":"pending","date_created":"2024-04-01 00:00:00","status":"pending"}
+		],"meta":{"total":5,"links":{}}}`)
+	})
+
+	var restored RestoreReq
+	mux.HandleFunc("/v2/instances/a/restore", func(writer http.ResponseWriter, request *http.Request) {
+		if err := json.NewDecoder(request.Body).Decode(&restored); err != nil {
+			t.Fatal(err)
+		}
+		writer.WriteHeader(http.StatusAccepted)
+	})
+
+	backup, err := RestoreLatestBackup(ctx, client, "a")
+	if err != nil {
+		t.Fatalf("RestoreLatestBackup returned %+v", err)
+	}
+
+	if backup.ID != "new" || restored.BackupID != "new" {
+		t.Errorf("RestoreLatestBackup restored %s from %s, expected new", restored.BackupID, backup.ID)
+	}
+}
+
+func TestRestoreLatestBackupNoBackups(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/backups", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"backups":[],"meta":{"total":0,"links":{}}}`)
+	})
+
+	if _, err := RestoreLatestBackup(ctx, client, "a"); !errors.Is(err, ErrNoBackups) {
+		t.Errorf("RestoreLatestBackup returned %+v, expected %+v", err, ErrNoBackups)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -33,6 +34,11 @@ type History struct {
 	Balance     float32 `json:"balance"`
 }
 
+// Time parses Date with ParseTimestamp
+func (h *History) Time() (time.Time, error) {
+	return ParseTimestamp(h.Date)
+}
+
 // Invoice represents an invoice on an account
 type Invoice struct {
 	ID          int     `json:"id"`
@@ -42,6 +48,11 @@ type Invoice struct {
 	Balance     float32 `json:"balance"`
 }
 
+// Time parses Date with ParseTimestamp
+func (i *Invoice) Time() (time.Time, error) {
+	return ParseTimestamp(i.Date)
+}
+
 // InvoiceItem represents an item on an accounts invoice
 type InvoiceItem struct {
 	Description string  `json:"description"`
@@ -54,6 +65,16 @@ type InvoiceItem struct {
 	Total       float32 `json:"total"`
 }
 
+// StartsAt parses StartDate with ParseTimestamp
+func (i *InvoiceItem) StartsAt() (time.Time, error) {
+	return ParseTimestamp(i.StartDate)
+}
+
+// EndsAt parses EndDate with ParseTimestamp
+func (i *InvoiceItem) EndsAt() (time.Time, error) {
+	return ParseTimestamp(i.EndDate)
+}
+
 type billingHistoryBase struct {
 	History []History `json:"billing_history"`
 	Meta    *Meta     `json:"meta"`
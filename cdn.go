@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 const cdnPath string = "/v2/cdns"
@@ -60,6 +61,16 @@ type CDNZone struct {
 	Regions       []string `json:"regions"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (c *CDNZone) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(c.DateCreated)
+}
+
+// LastPurgedAt parses DatePurged with ParseTimestamp
+func (c *CDNZone) LastPurgedAt() (time.Time, error) {
+	return ParseTimestamp(c.DatePurged)
+}
+
 // CDNZoneReq is the data used to create a push/pull zone
 type CDNZoneReq struct {
 	Label        string   `json:"label"`
@@ -89,6 +100,11 @@ type CDNZoneFile struct {
 	DateModified string `json:"last_modified"`
 }
 
+// ModifiedAt parses DateModified with ParseTimestamp
+func (c *CDNZoneFile) ModifiedAt() (time.Time, error) {
+	return ParseTimestamp(c.DateModified)
+}
+
 // CDNZoneEndpointReq is the data used to create a push zone upload endpoint
 type CDNZoneEndpointReq struct {
 	Name string `json:"name"`
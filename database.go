@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -159,7 +160,7 @@ type Database struct {
 	PlanReplicas           *int                 `json:"plan_replicas,omitempty"`
 	PlanBrokers            int                  `json:"plan_brokers,omitempty"`
 	Region                 string               `json:"region"`
-	DatabaseEngine         string               `json:"database_engine"`
+	DatabaseEngine         DatabaseEngine       `json:"database_engine"`
 	DatabaseEngineVersion  string               `json:"database_engine_version"`
 	VPCID                  string               `json:"vpc_id"`
 	Status                 string               `json:"status"`
@@ -198,6 +199,42 @@ type Database struct {
 	ReadReplicas           []Database           `json:"read_replicas,omitempty"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (d *Database) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(d.DateCreated)
+}
+
+// LatestBackupAt parses LatestBackup with ParseTimestamp
+func (d *Database) LatestBackupAt() (time.Time, error) {
+	return ParseTimestamp(d.LatestBackup)
+}
+
+// DatabaseEngine is the engine type of a Managed Database
+type DatabaseEngine string
+
+// Managed Database engines offered by the API
+const (
+	DatabaseEngineMySQL    DatabaseEngine = "mysql"
+	DatabaseEnginePG       DatabaseEngine = "pg"
+	DatabaseEngineValkey   DatabaseEngine = "valkey"
+	DatabaseEngineKafka    DatabaseEngine = "kafka"
+	DatabaseEngineFerretPG DatabaseEngine = "ferretpg"
+)
+
+// Valid reports whether the engine is one known to this version of govultr
+func (d DatabaseEngine) Valid() bool {
+	switch d {
+	case DatabaseEngineMySQL, DatabaseEnginePG, DatabaseEngineValkey, DatabaseEngineKafka, DatabaseEngineFerretPG:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (d DatabaseEngine) String() string {
+	return string(d)
+}
+
 // FerretDBCredentials represents connection details and IP address information for FerretDB engine type subscriptions
 type FerretDBCredentials struct {
 	Host      string `json:"host"`
@@ -227,26 +264,26 @@ type databaseBase struct {
 
 // DatabaseCreateReq struct used to create a database
 type DatabaseCreateReq struct {
-	DatabaseEngine         string   `json:"database_engine,omitempty"`
-	DatabaseEngineVersion  string   `json:"database_engine_version,omitempty"`
-	Region                 string   `json:"region,omitempty"`
-	Plan                   string   `json:"plan,omitempty"`
-	Label                  string   `json:"label,omitempty"`
-	Tag                    string   `json:"tag,omitempty"`
-	VPCID                  string   `json:"vpc_id,omitempty"`
-	MaintenanceDOW         string   `json:"maintenance_dow,omitempty"`
-	MaintenanceTime        string   `json:"maintenance_time,omitempty"`
-	BackupHour             *string  `json:"backup_hour,omitempty"`
-	BackupMinute           *string  `json:"backup_minute,omitempty"`
-	TrustedIPs             []string `json:"trusted_ips,omitempty"`
-	MySQLSQLModes          []string `json:"mysql_sql_modes,omitempty"`
-	MySQLRequirePrimaryKey *bool    `json:"mysql_require_primary_key,omitempty"`
-	MySQLSlowQueryLog      *bool    `json:"mysql_slow_query_log,omitempty"`
-	MySQLLongQueryTime     int      `json:"mysql_long_query_time,omitempty"`
-	EvictionPolicy         string   `json:"eviction_policy,omitempty"`
-	EnableKafkaREST        *bool    `json:"enable_kafka_rest,omitempty"`
-	EnableSchemaRegistry   *bool    `json:"enable_schema_registry,omitempty"`
-	EnableKafkaConnect     *bool    `json:"enable_kafka_connect,omitempty"`
+	DatabaseEngine         DatabaseEngine `json:"database_engine,omitempty"`
+	DatabaseEngineVersion  string         `json:"database_engine_version,omitempty"`
+	Region                 string         `json:"region,omitempty"`
+	Plan                   string         `json:"plan,omitempty"`
+	Label                  string         `json:"label,omitempty"`
+	Tag                    string         `json:"tag,omitempty"`
+	VPCID                  string         `json:"vpc_id,omitempty"`
+	MaintenanceDOW         string         `json:"maintenance_dow,omitempty"`
+	MaintenanceTime        string         `json:"maintenance_time,omitempty"`
+	BackupHour             *string        `json:"backup_hour,omitempty"`
+	BackupMinute           *string        `json:"backup_minute,omitempty"`
+	TrustedIPs             []string       `json:"trusted_ips,omitempty"`
+	MySQLSQLModes          []string       `json:"mysql_sql_modes,omitempty"`
+	MySQLRequirePrimaryKey *bool          `json:"mysql_require_primary_key,omitempty"`
+	MySQLSlowQueryLog      *bool          `json:"mysql_slow_query_log,omitempty"`
+	MySQLLongQueryTime     int            `json:"mysql_long_query_time,omitempty"`
+	EvictionPolicy         string         `json:"eviction_policy,omitempty"`
+	EnableKafkaREST        *bool          `json:"enable_kafka_rest,omitempty"`
+	EnableSchemaRegistry   *bool          `json:"enable_schema_registry,omitempty"`
+	EnableKafkaConnect     *bool          `json:"enable_kafka_connect,omitempty"`
 }
 
 // DatabaseUpdateReq struct used to update a database
@@ -549,6 +586,11 @@ type DatabaseAlert struct {
 	TableCount           int    `json:"table_count,omitempty"`
 }
 
+// Time parses Timestamp with ParseTimestamp
+func (d *DatabaseAlert) Time() (time.Time, error) {
+	return ParseTimestamp(d.Timestamp)
+}
+
 // databaseAlertsBase holds the API response for querying service alerts within a Managed Database
 type databaseAlertsBase struct {
 	DatabaseAlerts []DatabaseAlert `json:"alerts"`
@@ -0,0 +1,288 @@
+package govultr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	valkeyDefaultUser    = "default"
+	kafkaSASLMechanism   = "SCRAM-SHA-256"
+	pemBlockPrefix       = "-----BEGIN"
+	ferretDBDefaultDB    = "defaultdb"
+	kafkaSecuritySSL     = "SSL"
+	kafkaSecuritySASLSSL = "SASL_SSL"
+)
+
+// DatabaseConnOptions controls how connection details are derived from a Database.
+// The zero value connects to Host as the admin user.
+type DatabaseConnOptions struct {
+	// Public connects through PublicHost, or the public IP for FerretDB. Databases
+	// attached to a VPC report their VPC address in Host, which is used by default.
+	Public bool
+
+	// User and Password replace the admin credentials
+	User     string
+	Password string
+
+	// Database replaces DBName for engines that have databases
+	Database string
+
+	// Pool routes PostgreSQL connections through a connection pool. PgBouncer selects
+	// the pool by database name, so the pool name replaces the database and the pool
+	// user replaces the admin user unless User is set.
+	Pool *DatabaseConnectionPool
+
+	// SASL connects to Kafka with SCRAM credentials on SASLPort instead of using
+	// the client certificate on Port
+	SASL bool
+}
+
+// ConnectionHost returns the host and port a client should connect to
+func (d *Database) ConnectionHost(opts *DatabaseConnOptions) (string, error) {
+	if opts == nil {
+		opts = &DatabaseConnOptions{}
+	}
+
+	if d.DatabaseEngine == DatabaseEngineFerretPG {
+		return d.ferretDBHost(opts)
+	}
+
+	host := d.Host
+	if opts.Public && d.PublicHost != "" {
+		host = d.PublicHost
+	}
+
+	port := d.Port
+	if d.DatabaseEngine == DatabaseEngineKafka && opts.SASL {
+		if d.SASLPort == "" {
+			return "", errors.New("database does not have SASL authentication enabled")
+		}
+		port = d.SASLPort
+	}
+
+	if host == "" || port == "" {
+		return "", fmt.Errorf("database %s does not report a host and port", d.ID)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+func (d *Database) ferretDBHost(opts *DatabaseConnOptions) (string, error) {
+	creds := d.FerretDBCredentials
+	if creds == nil {
+		return "", fmt.Errorf("database %s does not report FerretDB credentials", d.ID)
+	}
+
+	host := creds.Host
+	switch {
+	case opts.Public && creds.PublicIP != "":
+		host = creds.PublicIP
+	case !opts.Public && d.VPCID != "" && creds.PrivateIP != "":
+		host = creds.PrivateIP
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(creds.Port)), nil
+}
+
+// ConnectionURI returns a connection URI in the form expected by clients of the
+// engine: postgres:// for PostgreSQL, mysql:// for MySQL, rediss:// for Valkey,
+// kafka:// for Kafka and mongodb:// for FerretDB. TLS is always required.
+func (d *Database) ConnectionURI(opts *DatabaseConnOptions) (string, error) {
+	if opts == nil {
+		opts = &DatabaseConnOptions{}
+	}
+
+	host, err := d.ConnectionHost(opts)
+	if err != nil {
+		return "", err
+	}
+
+	user, password := d.connectionCredentials(opts)
+	u := &url.URL{Host: host, User: url.UserPassword(user, password)}
+	query := url.Values{}
+
+	switch d.DatabaseEngine {
+	case DatabaseEnginePG:
+		u.Scheme, u.Path = "postgres", "/"+d.connectionDatabase(opts)
+		query.Set("sslmode", "require")
+	case DatabaseEngineMySQL:
+		u.Scheme, u.Path = "mysql", "/"+d.connectionDatabase(opts)
+		query.Set("ssl-mode", "REQUIRED")
+	case DatabaseEngineValkey:
+		u.Scheme = "rediss"
+	case DatabaseEngineKafka:
+		u.Scheme = "kafka"
+		if opts.SASL {
+			query.Set("security_protocol", kafkaSecuritySASLSSL)
+			query.Set("sasl_mechanism", kafkaSASLMechanism)
+		} else {
+			// client certificate authentication carries no credentials in the URI
+			u.User = nil
+			query.Set("security_protocol", kafkaSecuritySSL)
+		}
+	case DatabaseEngineFerretPG:
+		u.Scheme, u.Path = "mongodb", "/"+d.connectionDatabase(opts)
+		query.Set("tls", "true")
+	default:
+		return "", fmt.Errorf("unsupported database engine %q", d.DatabaseEngine)
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (d *Database) connectionCredentials(opts *DatabaseConnOptions) (user, password string) {
+	user, password = d.User, d.Password
+	if d.DatabaseEngine == DatabaseEngineFerretPG && d.FerretDBCredentials != nil {
+		user, password = d.FerretDBCredentials.User, d.FerretDBCredentials.Password
+	}
+	if d.DatabaseEngine == DatabaseEngineValkey && user == "" {
+		user = valkeyDefaultUser
+	}
+
+	if d.DatabaseEngine == DatabaseEnginePG && opts.Pool != nil && opts.Pool.Username != "" {
+		user = opts.Pool.Username
+	}
+	if opts.User != "" {
+		user = opts.User
+	}
+	if opts.Password != "" {
+		password = opts.Password
+	}
+
+	return user, password
+}
+
+func (d *Database) connectionDatabase(opts *DatabaseConnOptions) string {
+	switch {
+	case d.DatabaseEngine == DatabaseEnginePG && opts.Pool != nil:
+		return opts.Pool.Name
+	case opts.Database != "":
+		return opts.Database
+	case d.DBName != "":
+		return d.DBName
+	case d.DatabaseEngine == DatabaseEngineFerretPG:
+		return ferretDBDefaultDB
+	}
+	return ""
+}
+
+// KafkaProperties returns client properties in the librdkafka and Java client format
+// for connecting to a Kafka database. The CA certificate and, without SASL, the client
+// certificate and key are included as PEM strings.
+func (d *Database) KafkaProperties(opts *DatabaseConnOptions) (map[string]string, error) {
+	if d.DatabaseEngine != DatabaseEngineKafka {
+		return nil, fmt.Errorf("database %s is not a Kafka database", d.ID)
+	}
+	if opts == nil {
+		opts = &DatabaseConnOptions{}
+	}
+
+	host, err := d.ConnectionHost(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := decodePEM(d.CACertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	props := map[string]string{
+		"bootstrap.servers": host,
+		"ssl.ca.pem":        string(ca),
+	}
+
+	if opts.SASL {
+		user, password := d.connectionCredentials(opts)
+		props["security.protocol"] = kafkaSecuritySASLSSL
+		props["sasl.mechanism"] = kafkaSASLMechanism
+		props["sasl.username"] = user
+		props["sasl.password"] = password
+		return props, nil
+	}
+
+	cert, err := decodePEM(d.AccessCert)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodePEM(d.AccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	props["security.protocol"] = kafkaSecuritySSL
+	props["ssl.certificate.pem"] = string(cert)
+	props["ssl.key.pem"] = string(key)
+	return props, nil
+}
+
+// TLSConfig returns a TLS configuration that trusts the database CA certificate and
+// verifies the host chosen by opts. When the database has an access certificate and
+// key, as Kafka does, they are presented as the client certificate.
+func (d *Database) TLSConfig(opts *DatabaseConnOptions) (*tls.Config, error) {
+	hostPort, err := d.ConnectionHost(opts)
+	if err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+
+	if d.CACertificate != "" {
+		ca, err := decodePEM(d.CACertificate)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("database %s CA certificate contains no certificates", d.ID)
+		}
+	}
+
+	if d.AccessCert != "" && d.AccessKey != "" {
+		certPEM, err := decodePEM(d.AccessCert)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := decodePEM(d.AccessKey)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("loading database %s access certificate: %w", d.ID, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// decodePEM accepts PEM data as is or base64 encoded, both of which the API returns
+func decodePEM(data string) ([]byte, error) {
+	trimmed := strings.TrimSpace(data)
+	if trimmed == "" || strings.HasPrefix(trimmed, pemBlockPrefix) {
+		return []byte(data), nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(trimmed)
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(decoded)), pemBlockPrefix) {
+		return nil, errors.New("certificate is neither PEM nor base64 encoded PEM")
+	}
+
+	return decoded, nil
+}
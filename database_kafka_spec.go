@@ -0,0 +1,382 @@
+package govultr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrTopicPartitionsReduced is returned by ApplyKafkaSpec when the spec has fewer
+// partitions for a topic than it already has. Kafka cannot remove partitions.
+var ErrTopicPartitionsReduced = errors.New("kafka topic partitions cannot be reduced")
+
+// KafkaSpec is a portable description of the topics and quotas of a Kafka database
+type KafkaSpec struct {
+	Topics []DatabaseTopic `json:"topics,omitempty"`
+	Quotas []DatabaseQuota `json:"quotas,omitempty"`
+}
+
+// Validate checks that topics and quotas are complete and unique
+func (k *KafkaSpec) Validate() error {
+	v := newValidation("KafkaSpec")
+
+	topics := make(map[string]bool, len(k.Topics))
+	for idx := range k.Topics {
+		topic := &k.Topics[idx]
+		prefix := fmt.Sprintf("topics[%d].", idx)
+
+		v.required(prefix+"name", topic.Name)
+		if topics[topic.Name] {
+			v.addf(prefix+"name", "duplicate topic %s", topic.Name)
+		}
+		topics[topic.Name] = true
+
+		if topic.Partitions < 1 {
+			v.addf(prefix+"partitions", "must be at least 1")
+		}
+		if topic.Replication < 1 {
+			v.addf(prefix+"replication", "must be at least 1")
+		}
+	}
+
+	quotas := make(map[string]bool, len(k.Quotas))
+	for idx := range k.Quotas {
+		quota := &k.Quotas[idx]
+		prefix := fmt.Sprintf("quotas[%d].", idx)
+
+		if quota.ClientID == "" && quota.User == "" {
+			v.addf(prefix+"client_id", "client_id or user is required")
+		}
+		key := kafkaQuotaKey(quota)
+		if quotas[key] {
+			v.addf(prefix+"client_id", "duplicate quota for %s", describeKafkaQuota(quota))
+		}
+		quotas[key] = true
+	}
+
+	return v.err()
+}
+
+// KafkaSpecApplyOptions controls how ApplyKafkaSpec converges a Kafka database
+type KafkaSpecApplyOptions struct {
+	// DeleteTopics deletes topics that are not in the spec. Deleting a topic deletes
+	// its messages, so without it such topics are only reported.
+	DeleteTopics bool
+
+	// DeleteQuotas deletes quotas that are not in the spec
+	DeleteQuotas bool
+
+	// DryRun computes and reports the plan without changing anything
+	DryRun bool
+
+	// Output receives the human readable plan before it is applied
+	Output io.Writer
+}
+
+// KafkaTopicUpdate is a change to the settings of an existing topic
+type KafkaTopicUpdate struct {
+	Current DatabaseTopic
+	Desired DatabaseTopic
+}
+
+// KafkaQuotaUpdate is a change to the rates of an existing quota
+type KafkaQuotaUpdate struct {
+	Current DatabaseQuota
+	Desired DatabaseQuota
+}
+
+// KafkaSpecPlan lists the changes needed to converge a Kafka database to a KafkaSpec
+type KafkaSpecPlan struct {
+	DatabaseID   string
+	TopicCreates []DatabaseTopic
+	TopicUpdates []KafkaTopicUpdate
+	TopicDeletes []DatabaseTopic
+	// TopicsKept lists topics missing from the spec that are kept because
+	// DeleteTopics is not set
+	TopicsKept   []DatabaseTopic
+	QuotaCreates []DatabaseQuota
+	QuotaUpdates []KafkaQuotaUpdate
+	QuotaDeletes []DatabaseQuota
+}
+
+// Empty reports whether the database already matches the spec
+func (k *KafkaSpecPlan) Empty() bool {
+	return len(k.TopicCreates) == 0 && len(k.TopicUpdates) == 0 && len(k.TopicDeletes) == 0 &&
+		len(k.QuotaCreates) == 0 && len(k.QuotaUpdates) == 0 && len(k.QuotaDeletes) == 0
+}
+
+// String renders the plan for people to review
+func (k *KafkaSpecPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for database %s topics: %d to create, %d to update, %d to delete, %d kept\n",
+		k.DatabaseID, len(k.TopicCreates), len(k.TopicUpdates), len(k.TopicDeletes), len(k.TopicsKept))
+
+	for idx := range k.TopicCreates {
+		fmt.Fprintf(&b, "  + %s\n", describeKafkaTopic(&k.TopicCreates[idx]))
+	}
+	for idx := range k.TopicUpdates {
+		u := &k.TopicUpdates[idx]
+		fmt.Fprintf(&b, "  ~ %s -> %s\n", describeKafkaTopic(&u.Current), describeKafkaTopic(&u.Desired))
+	}
+	for idx := range k.TopicDeletes {
+		fmt.Fprintf(&b, "  - %s\n", describeKafkaTopic(&k.TopicDeletes[idx]))
+	}
+	for idx := range k.TopicsKept {
+		fmt.Fprintf(&b, "  ! %s is not in the spec and is kept\n", k.TopicsKept[idx].Name)
+	}
+
+	fmt.Fprintf(&b, "Plan for database %s quotas: %d to create, %d to update, %d to delete\n",
+		k.DatabaseID, len(k.QuotaCreates), len(k.QuotaUpdates), len(k.QuotaDeletes))
+
+	for idx := range k.QuotaCreates {
+		q := &k.QuotaCreates[idx]
+		fmt.Fprintf(&b, "  + %s %s\n", describeKafkaQuota(q), describeKafkaQuotaRates(q))
+	}
+	for idx := range k.QuotaUpdates {
+		u := &k.QuotaUpdates[idx]
+		fmt.Fprintf(&b, "  ~ %s %s -> %s\n", describeKafkaQuota(&u.Current),
+			describeKafkaQuotaRates(&u.Current), describeKafkaQuotaRates(&u.Desired))
+	}
+	for idx := range k.QuotaDeletes {
+		fmt.Fprintf(&b, "  - %s\n", describeKafkaQuota(&k.QuotaDeletes[idx]))
+	}
+
+	return b.String()
+}
+
+func describeKafkaTopic(t *DatabaseTopic) string {
+	return fmt.Sprintf("%s (partitions %d, replication %d, retention %dh/%d bytes)",
+		t.Name, t.Partitions, t.Replication, t.RetentionHours, t.RetentionBytes)
+}
+
+func describeKafkaQuota(q *DatabaseQuota) string {
+	return fmt.Sprintf("client %q user %q", q.ClientID, q.User)
+}
+
+func describeKafkaQuotaRates(q *DatabaseQuota) string {
+	return fmt.Sprintf("(consumer %d B/s, producer %d B/s, request %d%%)", q.ConsumerByteRate, q.ProducerByteRate, q.RequestPercentage)
+}
+
+func kafkaQuotaKey(q *DatabaseQuota) string {
+	return q.ClientID + "\x00" + q.User
+}
+
+// ExportKafkaSpec reads the topics and quotas of a Kafka database into a KafkaSpec
+func ExportKafkaSpec(ctx context.Context, client *Client, databaseID string) (*KafkaSpec, error) {
+	topics, _, _, err := client.Database.ListTopics(ctx, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, _, _, err := client.Database.ListQuotas(ctx, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaSpec{Topics: topics, Quotas: quotas}, nil
+}
+
+// ApplyKafkaSpec converges the topics and quotas of a Kafka database to spec. Topics are
+// matched by name and quotas by client ID and user. A spec that has fewer partitions
+// for a topic than it already has is refused with ErrTopicPartitionsReduced before
+// anything is changed. Topics and quotas missing from the spec are only deleted when
+// opts asks for it.
+func ApplyKafkaSpec(ctx context.Context, client *Client, databaseID string, spec *KafkaSpec, opts *KafkaSpecApplyOptions) (*KafkaSpecPlan, error) { //nolint:lll
+	if opts == nil {
+		opts = &KafkaSpecApplyOptions{}
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := ExportKafkaSpec(ctx, client, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planKafkaSpec(databaseID, current, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return plan, err
+		}
+	}
+
+	if opts.DryRun || plan.Empty() {
+		return plan, nil
+	}
+
+	return plan, applyKafkaSpec(ctx, client.Database, plan)
+}
+
+func planKafkaSpec(databaseID string, current, desired *KafkaSpec, opts *KafkaSpecApplyOptions) (*KafkaSpecPlan, error) {
+	plan := &KafkaSpecPlan{DatabaseID: databaseID}
+
+	topics := make(map[string]DatabaseTopic, len(current.Topics))
+	for _, topic := range current.Topics {
+		topics[topic.Name] = topic
+	}
+
+	var reduced []string
+	wanted := make(map[string]bool, len(desired.Topics))
+	for _, topic := range desired.Topics {
+		wanted[topic.Name] = true
+
+		existing, ok := topics[topic.Name]
+		switch {
+		case !ok:
+			plan.TopicCreates = append(plan.TopicCreates, topic)
+		case topic.Partitions < existing.Partitions:
+			reduced = append(reduced, fmt.Sprintf("%s from %d to %d", topic.Name, existing.Partitions, topic.Partitions))
+		case topic != existing:
+			plan.TopicUpdates = append(plan.TopicUpdates, KafkaTopicUpdate{Current: existing, Desired: topic})
+		}
+	}
+	if len(reduced) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTopicPartitionsReduced, strings.Join(reduced, ", "))
+	}
+
+	for _, topic := range current.Topics {
+		switch {
+		case wanted[topic.Name]:
+		case opts.DeleteTopics:
+			plan.TopicDeletes = append(plan.TopicDeletes, topic)
+		default:
+			plan.TopicsKept = append(plan.TopicsKept, topic)
+		}
+	}
+
+	quotas := make(map[string]DatabaseQuota, len(current.Quotas))
+	for idx := range current.Quotas {
+		quotas[kafkaQuotaKey(&current.Quotas[idx])] = current.Quotas[idx]
+	}
+
+	wanted = make(map[string]bool, len(desired.Quotas))
+	for idx := range desired.Quotas {
+		quota := desired.Quotas[idx]
+		key := kafkaQuotaKey(&quota)
+		wanted[key] = true
+
+		if existing, ok := quotas[key]; !ok {
+			plan.QuotaCreates = append(plan.QuotaCreates, quota)
+		} else if quota != existing {
+			plan.QuotaUpdates = append(plan.QuotaUpdates, KafkaQuotaUpdate{Current: existing, Desired: quota})
+		}
+	}
+
+	if opts.DeleteQuotas {
+		for idx := range current.Quotas {
+			if !wanted[kafkaQuotaKey(&current.Quotas[idx])] {
+				plan.QuotaDeletes = append(plan.QuotaDeletes, current.Quotas[idx])
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// applyKafkaSpec creates and updates topics before quotas and deletes topics last, so
+// a failure part way never leaves the database with fewer topics than before
+func applyKafkaSpec(ctx context.Context, db DatabaseService, plan *KafkaSpecPlan) error {
+	for idx := range plan.TopicCreates {
+		t := &plan.TopicCreates[idx]
+		req := &DatabaseTopicCreateReq{
+			Name:           t.Name,
+			Partitions:     t.Partitions,
+			Replication:    t.Replication,
+			RetentionHours: t.RetentionHours,
+			RetentionBytes: t.RetentionBytes,
+		}
+		if _, _, err := db.CreateTopic(ctx, plan.DatabaseID, req); err != nil {
+			return fmt.Errorf("creating topic %s: %w", t.Name, err)
+		}
+	}
+
+	for idx := range plan.TopicUpdates {
+		t := &plan.TopicUpdates[idx].Desired
+		req := &DatabaseTopicUpdateReq{
+			Partitions:     t.Partitions,
+			Replication:    t.Replication,
+			RetentionHours: t.RetentionHours,
+			RetentionBytes: t.RetentionBytes,
+		}
+		if _, _, err := db.UpdateTopic(ctx, plan.DatabaseID, t.Name, req); err != nil {
+			return fmt.Errorf("updating topic %s: %w", t.Name, err)
+		}
+	}
+
+	if err := applyKafkaQuotas(ctx, db, plan); err != nil {
+		return err
+	}
+
+	for idx := range plan.TopicDeletes {
+		if err := db.DeleteTopic(ctx, plan.DatabaseID, plan.TopicDeletes[idx].Name); err != nil {
+			return fmt.Errorf("deleting topic %s: %w", plan.TopicDeletes[idx].Name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyKafkaQuotas(ctx context.Context, db DatabaseService, plan *KafkaSpecPlan) error {
+	for idx := range plan.QuotaCreates {
+		q := &plan.QuotaCreates[idx]
+		req := &DatabaseQuotaCreateReq{
+			ClientID:          q.ClientID,
+			User:              q.User,
+			ConsumerByteRate:  q.ConsumerByteRate,
+			ProducerByteRate:  q.ProducerByteRate,
+			RequestPercentage: q.RequestPercentage,
+		}
+		if _, _, err := db.CreateQuota(ctx, plan.DatabaseID, req); err != nil {
+			return fmt.Errorf("creating quota for %s: %w", describeKafkaQuota(q), err)
+		}
+	}
+
+	for idx := range plan.QuotaUpdates {
+		q := &plan.QuotaUpdates[idx].Desired
+		req := &DatabaseQuotaUpdateReq{
+			ConsumerByteRate:  q.ConsumerByteRate,
+			ProducerByteRate:  q.ProducerByteRate,
+			RequestPercentage: q.RequestPercentage,
+		}
+		if _, _, err := db.UpdateQuota(ctx, plan.DatabaseID, q.ClientID, q.User, req); err != nil {
+			return fmt.Errorf("updating quota for %s: %w", describeKafkaQuota(q), err)
+		}
+	}
+
+	for idx := range plan.QuotaDeletes {
+		q := &plan.QuotaDeletes[idx]
+		if err := db.DeleteQuota(ctx, plan.DatabaseID, q.ClientID, q.User); err != nil {
+			return fmt.Errorf("deleting quota for %s: %w", describeKafkaQuota(q), err)
+		}
+	}
+
+	return nil
+}
+
+// ReadKafkaSpec reads a Kafka spec document in JSON or YAML format
+func ReadKafkaSpec(r io.Reader) (*KafkaSpec, error) {
+	spec := &KafkaSpec{}
+	if err := readDocument(r, spec); err != nil {
+		return nil, fmt.Errorf("reading kafka spec: %w", err)
+	}
+	return spec, nil
+}
+
+// JSON returns the spec as an indented JSON document
+func (k *KafkaSpec) JSON() ([]byte, error) {
+	return json.MarshalIndent(k, "", "  ")
+}
+
+// YAML returns the spec as a YAML document
+func (k *KafkaSpec) YAML() ([]byte, error) {
+	return marshalYAML(k)
+}
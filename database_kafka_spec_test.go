@@ -0,0 +1,258 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	kafkaTopicsResponse = `{"topics":[
+		{"name":"orders","partitions":6,"replication":3,"retention_hours":168,"retention_bytes":-1},
+		{"name":"events","partitions":3,"replication":3,"retention_hours":24,"retention_bytes":-1},
+		{"name":"scratch","partitions":1,"replication":2,"retention_hours":1,"retention_bytes":-1}
+	],"meta":{"total":3,"links":{}}}`
+	kafkaQuotasResponse = `{"quotas":[
+		{"client_id":"billing","user":"","consumer_byte_rate":1048576,"producer_byte_rate":1048576,"request_percentage":50},
+		{"client_id":"","user":"batch","consumer_byte_rate":0,"producer_byte_rate":524288,"request_percentage":25}
+	],"meta":{"total":2,"links":{}}}`
+)
+
+var testKafkaSpec = KafkaSpec{
+	Topics: []DatabaseTopic{
+		{Name: "orders", Partitions: 6, Replication: 3, RetentionHours: 168, RetentionBytes: -1},
+		{Name: "events", Partitions: 12, Replication: 3, RetentionHours: 72, RetentionBytes: -1},
+		{Name: "audit", Partitions: 3, Replication: 3, RetentionHours: 720, RetentionBytes: 1073741824},
+	},
+	Quotas: []DatabaseQuota{
+		{ClientID: "billing", ConsumerByteRate: 2097152, ProducerByteRate: 1048576, RequestPercentage: 50},
+		{ClientID: "reports", User: "analyst", ConsumerByteRate: 1048576},
+	},
+}
+
+func registerKafkaSpecHandlers(t *testing.T, calls *[]string) {
+	mux.HandleFunc("/v2/databases/db/topics", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, kafkaTopicsResponse)
+			return
+		}
+		var req DatabaseTopicCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		*calls = append(*calls, fmt.Sprintf("create topic %s/%d", req.Name, req.Partitions))
+		fmt.Fprintf(writer, `{"topic":{"name":%q}}`, req.Name)
+	})
+	mux.HandleFunc("/v2/databases/db/topics/", func(writer http.ResponseWriter, request *http.Request) {
+		name := strings.TrimPrefix(request.URL.Path, "/v2/databases/db/topics/")
+		switch request.Method {
+		case http.MethodPut:
+			var req DatabaseTopicUpdateReq
+			if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			*calls = append(*calls, fmt.Sprintf("update topic %s/%d/%dh", name, req.Partitions, req.RetentionHours))
+			fmt.Fprintf(writer, `{"topic":{"name":%q}}`, name)
+		case http.MethodDelete:
+			*calls = append(*calls, "delete topic "+name)
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Request method = %v, expecting PUT or DELETE", request.Method)
+		}
+	})
+	mux.HandleFunc("/v2/databases/db/quotas", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, kafkaQuotasResponse)
+			return
+		}
+		var req DatabaseQuotaCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		*calls = append(*calls, fmt.Sprintf("create quota %s/%s", req.ClientID, req.User))
+		fmt.Fprint(writer, `{"quota":{}}`)
+	})
+	mux.HandleFunc("/v2/databases/db/quotas/", func(writer http.ResponseWriter, request *http.Request) {
+		path := strings.TrimPrefix(request.URL.Path, "/v2/databases/db/quotas/")
+		switch request.Method {
+		case http.MethodPut:
+			var req DatabaseQuotaUpdateReq
+			if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			*calls = append(*calls, fmt.Sprintf("update quota %s/%d", path, req.ConsumerByteRate))
+			fmt.Fprint(writer, `{"quota":{}}`)
+		case http.MethodDelete:
+			*calls = append(*calls, "delete quota "+path)
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Request method = %v, expecting PUT or DELETE", request.Method)
+		}
+	})
+}
+
+func TestApplyKafkaSpec(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	registerKafkaSpecHandlers(t, &calls)
+
+	var out bytes.Buffer
+	plan, err := ApplyKafkaSpec(ctx, client, "db", &testKafkaSpec, &KafkaSpecApplyOptions{Output: &out})
+	if err != nil {
+		t.Fatalf("ApplyKafkaSpec returned %+v", err)
+	}
+
+	expected := []string{
+		"create topic audit/3",
+		"update topic events/12/72h",
+		"create quota reports/analyst",
+		"update quota billing//2097152",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v, expected %v", calls, expected)
+	}
+
+	if len(plan.TopicsKept) != 1 || plan.TopicsKept[0].Name != "scratch" || len(plan.TopicDeletes) != 0 {
+		t.Errorf("plan kept %+v and deleted %+v, expected scratch to be kept", plan.TopicsKept, plan.TopicDeletes)
+	}
+	if len(plan.QuotaDeletes) != 0 {
+		t.Errorf("QuotaDeletes = %+v, expected none without DeleteQuotas", plan.QuotaDeletes)
+	}
+	if !strings.Contains(out.String(), "! scratch is not in the spec and is kept") {
+		t.Errorf("output = %q, expected the kept topic to be reported", out.String())
+	}
+}
+
+func TestApplyKafkaSpecDeletes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	registerKafkaSpecHandlers(t, &calls)
+
+	spec := &KafkaSpec{Topics: testKafkaSpec.Topics[:2]}
+	_, err := ApplyKafkaSpec(ctx, client, "db", spec, &KafkaSpecApplyOptions{DeleteTopics: true, DeleteQuotas: true})
+	if err != nil {
+		t.Fatalf("ApplyKafkaSpec returned %+v", err)
+	}
+
+	// topics are deleted after everything else
+	expected := []string{
+		"update topic events/12/72h",
+		"delete quota billing/",
+		"delete quota batch",
+		"delete topic scratch",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v, expected %v", calls, expected)
+	}
+}
+
+func TestApplyKafkaSpecPartitionsReduced(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	registerKafkaSpecHandlers(t, &calls)
+
+	spec := &KafkaSpec{Topics: []DatabaseTopic{
+		{Name: "orders", Partitions: 3, Replication: 3},
+		{Name: "audit", Partitions: 3, Replication: 3},
+	}}
+	plan, err := ApplyKafkaSpec(ctx, client, "db", spec, nil)
+	if !errors.Is(err, ErrTopicPartitionsReduced) {
+		t.Fatalf("ApplyKafkaSpec returned %+v, expected ErrTopicPartitionsReduced", err)
+	}
+	if !strings.Contains(err.Error(), "orders from 6 to 3") {
+		t.Errorf("error = %q, expected it to name the topic", err)
+	}
+	if plan != nil || len(calls) != 0 {
+		t.Errorf("plan = %+v, calls = %v, expected nothing to be applied", plan, calls)
+	}
+}
+
+func TestApplyKafkaSpecDryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	registerKafkaSpecHandlers(t, &calls)
+
+	plan, err := ApplyKafkaSpec(ctx, client, "db", &testKafkaSpec, &KafkaSpecApplyOptions{DryRun: true, DeleteTopics: true})
+	if err != nil {
+		t.Fatalf("ApplyKafkaSpec returned %+v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("calls = %v, expected none on a dry run", calls)
+	}
+	if len(plan.TopicCreates) != 1 || len(plan.TopicUpdates) != 1 || len(plan.TopicDeletes) != 1 ||
+		len(plan.QuotaCreates) != 1 || len(plan.QuotaUpdates) != 1 {
+		t.Errorf("plan = %s", plan)
+	}
+}
+
+func TestKafkaSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   KafkaSpec
+		fields []string
+	}{
+		{name: "valid", spec: testKafkaSpec},
+		{
+			name: "invalid topics",
+			spec: KafkaSpec{Topics: []DatabaseTopic{
+				{Partitions: 1, Replication: 1},
+				{Name: "a", Partitions: 1, Replication: 1},
+				{Name: "a"},
+			}},
+			fields: []string{"topics[0].name", "topics[2].name", "topics[2].partitions", "topics[2].replication"},
+		},
+		{
+			name: "invalid quotas",
+			spec: KafkaSpec{Quotas: []DatabaseQuota{
+				{ConsumerByteRate: 1},
+				{ClientID: "a", User: "b"},
+				{ClientID: "a", User: "b"},
+			}},
+			fields: []string{"quotas[0].client_id", "quotas[2].client_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.spec.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestReadKafkaSpec(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var data []byte
+			var err error
+			if format == "json" {
+				data, err = testKafkaSpec.JSON()
+			} else {
+				data, err = testKafkaSpec.YAML()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec, err := ReadKafkaSpec(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ReadKafkaSpec returned %+v for\n%s", err, data)
+			}
+			if !reflect.DeepEqual(spec, &testKafkaSpec) {
+				t.Errorf("ReadKafkaSpec returned %+v, expected %+v", spec, testKafkaSpec)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package govultr
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+const (
+	databasePasswordLength  = 32
+	databasePasswordCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// DatabaseUserSpec is the desired state of a database user
+type DatabaseUserSpec struct {
+	Username string
+	// Password is used for new users and rotations. When empty the API generates the
+	// password of new users and rotations generate a random one.
+	Password string
+	// Encryption is the MySQL authentication plugin of new users
+	Encryption string
+	// Permission is the Kafka topic permission of the user. Empty leaves it unmanaged.
+	Permission string
+	// AccessControl is the Valkey ACL of the user. Nil leaves it unmanaged.
+	AccessControl *DatabaseUserACL
+	// RotatePassword sets a new password on an existing user
+	RotatePassword bool
+}
+
+// DatabaseUserSyncOptions controls how SyncDatabaseUsers converges the users of a database
+type DatabaseUserSyncOptions struct {
+	// Prune deletes users that are not desired. The admin user of the database and
+	// the users in Keep are never deleted.
+	Prune bool
+	Keep  []string
+
+	// DryRun computes and reports the plan without changing anything
+	DryRun bool
+
+	// Output receives the human readable plan before it is applied
+	Output io.Writer
+}
+
+// DatabaseUserCredentials holds the secrets of a created user or a rotated password.
+// Its String and GoString methods hide the secrets so printing it does not leak them.
+type DatabaseUserCredentials struct {
+	Username   string
+	Password   string
+	AccessKey  string
+	AccessCert string
+}
+
+func (c DatabaseUserCredentials) String() string {
+	return c.Username + ":<redacted>"
+}
+
+// GoString hides the secrets from the %#v verb
+func (c DatabaseUserCredentials) GoString() string {
+	return fmt.Sprintf("DatabaseUserCredentials{Username:%q}", c.Username)
+}
+
+// DatabaseUserACLUpdate is a change to the access control of a user
+type DatabaseUserACLUpdate struct {
+	Username string
+	Current  DatabaseUserACLReq
+	Desired  DatabaseUserACLReq
+}
+
+// DatabaseUserSyncPlan lists the changes needed to converge the users of a database.
+// Users are identified by username only so the plan can be printed without secrets.
+type DatabaseUserSyncPlan struct {
+	DatabaseID string
+	Creates    []DatabaseUserSpec
+	ACLUpdates []DatabaseUserACLUpdate
+	Rotations  []DatabaseUserSpec
+	Deletes    []string
+	Unchanged  []string
+}
+
+// Empty reports whether the database already has the desired users
+func (d *DatabaseUserSyncPlan) Empty() bool {
+	return len(d.Creates) == 0 && len(d.ACLUpdates) == 0 && len(d.Rotations) == 0 && len(d.Deletes) == 0
+}
+
+// String renders the plan for people to review. Passwords are never included.
+func (d *DatabaseUserSyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for database %s users: %d to create, %d to update, %d to rotate, %d to delete, %d unchanged\n",
+		d.DatabaseID, len(d.Creates), len(d.ACLUpdates), len(d.Rotations), len(d.Deletes), len(d.Unchanged))
+
+	for idx := range d.Creates {
+		fmt.Fprintf(&b, "  + %s\n", d.Creates[idx].Username)
+	}
+	for idx := range d.ACLUpdates {
+		u := &d.ACLUpdates[idx]
+		fmt.Fprintf(&b, "  ~ %s (%s)\n", u.Username, describeACLChange(&u.Current, &u.Desired))
+	}
+	for idx := range d.Rotations {
+		fmt.Fprintf(&b, "  ~ %s (password)\n", d.Rotations[idx].Username)
+	}
+	for _, username := range d.Deletes {
+		fmt.Fprintf(&b, "  - %s\n", username)
+	}
+
+	return b.String()
+}
+
+func describeACLChange(current, desired *DatabaseUserACLReq) string {
+	var changes []string
+	if desired.Permission != current.Permission {
+		changes = append(changes, fmt.Sprintf("permission %q -> %q", current.Permission, desired.Permission))
+	}
+
+	lists := []struct {
+		name             string
+		current, desired *[]string
+	}{
+		{"categories", current.ACLCategories, desired.ACLCategories},
+		{"channels", current.ACLChannels, desired.ACLChannels},
+		{"commands", current.ACLCommands, desired.ACLCommands},
+		{"keys", current.ACLKeys, desired.ACLKeys},
+	}
+	for _, list := range lists {
+		if list.desired == nil {
+			continue
+		}
+		if !equalStringSets(derefStrings(list.current), derefStrings(list.desired)) {
+			changes = append(changes, fmt.Sprintf("%s [%s] -> [%s]", list.name,
+				strings.Join(derefStrings(list.current), " "), strings.Join(derefStrings(list.desired), " ")))
+		}
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+// SyncDatabaseUsers converges the users of a database to desired. Missing users are
+// created, the Kafka permission and Valkey ACL of existing users are updated, passwords
+// are rotated where requested and, with opts.Prune, unmanaged users are deleted. The
+// secrets of created and rotated users are returned, as the API only reveals
+// generated passwords in the response that creates them.
+func SyncDatabaseUsers(ctx context.Context, client *Client, databaseID string, desired []DatabaseUserSpec, opts *DatabaseUserSyncOptions) (*DatabaseUserSyncPlan, []DatabaseUserCredentials, error) { //nolint:lll
+	db := client.Database
+	if opts == nil {
+		opts = &DatabaseUserSyncOptions{}
+	}
+
+	database, _, err := db.Get(ctx, databaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateDatabaseUserSpecs(database.DatabaseEngine, desired); err != nil {
+		return nil, nil, err
+	}
+
+	current, _, _, err := db.ListUsers(ctx, databaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keep := append([]string{database.User}, opts.Keep...)
+	plan := planDatabaseUserSync(databaseID, current, desired, opts.Prune, keep)
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return plan, nil, err
+		}
+	}
+
+	if opts.DryRun || plan.Empty() {
+		return plan, nil, nil
+	}
+
+	creds, err := applyDatabaseUserSync(ctx, db, plan)
+	return plan, creds, err
+}
+
+func validateDatabaseUserSpecs(engine DatabaseEngine, desired []DatabaseUserSpec) error {
+	v := newValidation("DatabaseUserSpecs")
+
+	seen := make(map[string]bool, len(desired))
+	for idx := range desired {
+		spec := &desired[idx]
+		prefix := fmt.Sprintf("[%d].", idx)
+
+		v.required(prefix+"username", spec.Username)
+		if seen[spec.Username] {
+			v.addf(prefix+"username", "duplicate user %s", spec.Username)
+		}
+		seen[spec.Username] = true
+
+		if spec.Permission != "" && engine != DatabaseEngineKafka {
+			v.addf(prefix+"permission", "is only supported by kafka databases, not %s", engine)
+		}
+		if spec.AccessControl != nil && engine != DatabaseEngineValkey {
+			v.addf(prefix+"access_control", "is only supported by valkey databases, not %s", engine)
+		}
+		if spec.Encryption != "" && engine != DatabaseEngineMySQL {
+			v.addf(prefix+"encryption", "is only supported by mysql databases, not %s", engine)
+		}
+	}
+
+	return v.err()
+}
+
+func planDatabaseUserSync(databaseID string, current []DatabaseUser, desired []DatabaseUserSpec, prune bool, keep []string) *DatabaseUserSyncPlan { //nolint:lll
+	plan := &DatabaseUserSyncPlan{DatabaseID: databaseID}
+
+	existing := make(map[string]*DatabaseUser, len(current))
+	for idx := range current {
+		existing[current[idx].Username] = &current[idx]
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for idx := range desired {
+		spec := desired[idx]
+		wanted[spec.Username] = true
+
+		user, ok := existing[spec.Username]
+		if !ok {
+			plan.Creates = append(plan.Creates, spec)
+			// the create call takes the permission but not the ACL
+			if spec.AccessControl != nil {
+				plan.ACLUpdates = append(plan.ACLUpdates, DatabaseUserACLUpdate{
+					Username: spec.Username,
+					Desired:  databaseUserACLReq(spec.AccessControl, ""),
+				})
+			}
+			continue
+		}
+
+		changed := false
+		if update, ok := planDatabaseUserACL(user, &spec); ok {
+			plan.ACLUpdates = append(plan.ACLUpdates, update)
+			changed = true
+		}
+		if spec.RotatePassword {
+			plan.Rotations = append(plan.Rotations, spec)
+			changed = true
+		}
+		if !changed {
+			plan.Unchanged = append(plan.Unchanged, spec.Username)
+		}
+	}
+
+	if prune {
+		for idx := range current {
+			username := current[idx].Username
+			if !wanted[username] && !containsString(keep, username) {
+				plan.Deletes = append(plan.Deletes, username)
+			}
+		}
+		sort.Strings(plan.Deletes)
+	}
+
+	return plan
+}
+
+// planDatabaseUserACL compares the managed parts of the access control of user with spec
+func planDatabaseUserACL(user *DatabaseUser, spec *DatabaseUserSpec) (DatabaseUserACLUpdate, bool) {
+	update := DatabaseUserACLUpdate{
+		Username: user.Username,
+		Current:  databaseUserACLReq(user.AccessControl, user.Permission),
+	}
+
+	changed := false
+	if spec.Permission != "" {
+		update.Desired.Permission = spec.Permission
+		changed = spec.Permission != user.Permission
+	}
+
+	if spec.AccessControl != nil {
+		desired := databaseUserACLReq(spec.AccessControl, "")
+		update.Desired.ACLCategories = desired.ACLCategories
+		update.Desired.ACLChannels = desired.ACLChannels
+		update.Desired.ACLCommands = desired.ACLCommands
+		update.Desired.ACLKeys = desired.ACLKeys
+
+		current := user.AccessControl
+		if current == nil {
+			current = &DatabaseUserACL{}
+		}
+		if !equalStringSets(current.ACLCategories, spec.AccessControl.ACLCategories) ||
+			!equalStringSets(current.ACLChannels, spec.AccessControl.ACLChannels) ||
+			!equalStringSets(current.ACLCommands, spec.AccessControl.ACLCommands) ||
+			!equalStringSets(current.ACLKeys, spec.AccessControl.ACLKeys) {
+			changed = true
+		}
+	}
+
+	return update, changed
+}
+
+func databaseUserACLReq(acl *DatabaseUserACL, permission string) DatabaseUserACLReq {
+	req := DatabaseUserACLReq{Permission: permission}
+	if acl != nil {
+		req.ACLCategories = copyStrings(acl.ACLCategories)
+		req.ACLChannels = copyStrings(acl.ACLChannels)
+		req.ACLCommands = copyStrings(acl.ACLCommands)
+		req.ACLKeys = copyStrings(acl.ACLKeys)
+	}
+	return req
+}
+
+// copyStrings returns a pointer to a copy of s that is never nil, so an empty list is
+// sent to the API rather than omitted
+func copyStrings(s []string) *[]string {
+	c := append([]string{}, s...)
+	return &c
+}
+
+func derefStrings(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// equalStringSets reports whether a and b hold the same strings in any order
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string{}, a...)
+	sb := append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for idx := range sa {
+		if sa[idx] != sb[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDatabaseUserSync creates users before updating access control, so new Valkey
+// users get their ACL, and deletes last. The credentials gathered so far are returned
+// even when a step fails.
+func applyDatabaseUserSync(ctx context.Context, db DatabaseService, plan *DatabaseUserSyncPlan) ([]DatabaseUserCredentials, error) {
+	var creds []DatabaseUserCredentials
+
+	for idx := range plan.Creates {
+		spec := &plan.Creates[idx]
+		user, _, err := db.CreateUser(ctx, plan.DatabaseID, &DatabaseUserCreateReq{
+			Username:   spec.Username,
+			Password:   spec.Password,
+			Encryption: spec.Encryption,
+			Permission: spec.Permission,
+		})
+		if err != nil {
+			return creds, fmt.Errorf("creating user %s: %w", spec.Username, err)
+		}
+		creds = append(creds, databaseUserCredentials(user, spec.Username))
+	}
+
+	for idx := range plan.ACLUpdates {
+		update := &plan.ACLUpdates[idx]
+		if _, _, err := db.UpdateUserACL(ctx, plan.DatabaseID, update.Username, &update.Desired); err != nil {
+			return creds, fmt.Errorf("updating access control of user %s: %w", update.Username, err)
+		}
+	}
+
+	for idx := range plan.Rotations {
+		spec := &plan.Rotations[idx]
+		password := spec.Password
+		if password == "" {
+			var err error
+			if password, err = generateDatabasePassword(); err != nil {
+				return creds, err
+			}
+		}
+
+		user, _, err := db.UpdateUser(ctx, plan.DatabaseID, spec.Username, &DatabaseUserUpdateReq{Password: password})
+		if err != nil {
+			return creds, fmt.Errorf("rotating password of user %s: %w", spec.Username, err)
+		}
+		rotated := databaseUserCredentials(user, spec.Username)
+		rotated.Password = password
+		creds = append(creds, rotated)
+	}
+
+	for _, username := range plan.Deletes {
+		if err := db.DeleteUser(ctx, plan.DatabaseID, username); err != nil {
+			return creds, fmt.Errorf("deleting user %s: %w", username, err)
+		}
+	}
+
+	return creds, nil
+}
+
+func databaseUserCredentials(user *DatabaseUser, username string) DatabaseUserCredentials {
+	creds := DatabaseUserCredentials{Username: username}
+	if user != nil {
+		creds.Password = user.Password
+		creds.AccessKey = user.AccessKey
+		creds.AccessCert = user.AccessCert
+	}
+	return creds
+}
+
+// generateDatabasePassword returns a random alphanumeric password, which every engine accepts
+func generateDatabasePassword() (string, error) {
+	limit := big.NewInt(int64(len(databasePasswordCharset)))
+	password := make([]byte, databasePasswordLength)
+	for idx := range password {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("generating password: %w", err)
+		}
+		password[idx] = databasePasswordCharset[n.Int64()]
+	}
+	return string(password), nil
+}
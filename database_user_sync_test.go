@@ -0,0 +1,248 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanDatabaseUserSync(t *testing.T) {
+	current := []DatabaseUser{
+		{Username: "default", Password: "admin-secret"},
+		{Username: "app", AccessControl: &DatabaseUserACL{ACLCategories: []string{"+@read", "+@write"}, ACLKeys: []string{"app:*"}}},
+		{Username: "reports", AccessControl: &DatabaseUserACL{ACLCategories: []string{"+@read"}}},
+		{Username: "legacy"},
+		{Username: "monitor"},
+	}
+
+	desired := []DatabaseUserSpec{
+		// same sets in a different order
+		{Username: "app", AccessControl: &DatabaseUserACL{ACLCategories: []string{"+@write", "+@read"}, ACLKeys: []string{"app:*"}}},
+		{Username: "reports", AccessControl: &DatabaseUserACL{ACLCategories: []string{"+@read"}, ACLKeys: []string{"report:*"}}},
+		{Username: "worker", Password: "worker-secret", AccessControl: &DatabaseUserACL{ACLKeys: []string{"jobs:*"}}},
+		{Username: "legacy", RotatePassword: true},
+	}
+
+	plan := planDatabaseUserSync("db", current, desired, true, []string{"default", "monitor"})
+
+	if len(plan.Creates) != 1 || plan.Creates[0].Username != "worker" {
+		t.Errorf("Creates = %+v, expected worker", plan.Creates)
+	}
+
+	var updated []string
+	for idx := range plan.ACLUpdates {
+		updated = append(updated, plan.ACLUpdates[idx].Username)
+	}
+	if !reflect.DeepEqual(updated, []string{"reports", "worker"}) {
+		t.Errorf("ACLUpdates = %v, expected [reports worker]", updated)
+	}
+	if keys := *plan.ACLUpdates[0].Desired.ACLKeys; !reflect.DeepEqual(keys, []string{"report:*"}) {
+		t.Errorf("reports keys = %v, expected [report:*]", keys)
+	}
+
+	if len(plan.Rotations) != 1 || plan.Rotations[0].Username != "legacy" {
+		t.Errorf("Rotations = %+v, expected legacy", plan.Rotations)
+	}
+	if len(plan.Deletes) != 0 {
+		t.Errorf("Deletes = %v, expected none as the other users are kept", plan.Deletes)
+	}
+	if !reflect.DeepEqual(plan.Unchanged, []string{"app"}) {
+		t.Errorf("Unchanged = %v, expected [app]", plan.Unchanged)
+	}
+
+	out := plan.String()
+	for _, want := range []string{"+ worker", "~ reports (keys [] -> [report:*])", "~ legacy (password)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("String() = %q, expected it to contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "worker-secret") {
+		t.Errorf("String() = %q leaks a password", out)
+	}
+
+	pruned := planDatabaseUserSync("db", current, desired, true, []string{"default"})
+	if !reflect.DeepEqual(pruned.Deletes, []string{"monitor"}) {
+		t.Errorf("Deletes = %v, expected [monitor]", pruned.Deletes)
+	}
+}
+
+func TestPlanDatabaseUserSync_Permission(t *testing.T) {
+	current := []DatabaseUser{
+		{Username: "producer", Permission: "write"},
+		{Username: "consumer", Permission: "read"},
+	}
+	desired := []DatabaseUserSpec{
+		{Username: "producer", Permission: "readwrite"},
+		{Username: "consumer", Permission: "read"},
+		{Username: "audit"},
+	}
+
+	plan := planDatabaseUserSync("db", current, desired, false, nil)
+
+	if len(plan.ACLUpdates) != 1 || plan.ACLUpdates[0].Desired.Permission != "readwrite" {
+		t.Fatalf("ACLUpdates = %+v, expected producer to readwrite", plan.ACLUpdates)
+	}
+	if plan.ACLUpdates[0].Desired.ACLCategories != nil {
+		t.Errorf("Desired ACL lists = %+v, expected unmanaged lists to be omitted", plan.ACLUpdates[0].Desired)
+	}
+	if len(plan.Creates) != 1 || plan.Creates[0].Username != "audit" {
+		t.Errorf("Creates = %+v, expected audit", plan.Creates)
+	}
+	if len(plan.Deletes) != 0 {
+		t.Errorf("Deletes = %v, expected none without prune", plan.Deletes)
+	}
+	if !strings.Contains(plan.String(), `producer (permission "write" -> "readwrite")`) {
+		t.Errorf("String() = %q, expected the permission change", plan.String())
+	}
+}
+
+func TestValidateDatabaseUserSpecs(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine DatabaseEngine
+		specs  []DatabaseUserSpec
+		fields []string
+	}{
+		{
+			name:   "valid kafka",
+			engine: DatabaseEngineKafka,
+			specs:  []DatabaseUserSpec{{Username: "a", Permission: "read"}, {Username: "b"}},
+		},
+		{
+			name:   "missing and duplicate usernames",
+			engine: DatabaseEngineValkey,
+			specs:  []DatabaseUserSpec{{}, {Username: "a"}, {Username: "a"}},
+			fields: []string{"[0].username", "[2].username"},
+		},
+		{
+			name:   "fields of other engines",
+			engine: DatabaseEnginePG,
+			specs: []DatabaseUserSpec{{
+				Username:      "a",
+				Permission:    "read",
+				Encryption:    "caching_sha2_password",
+				AccessControl: &DatabaseUserACL{},
+			}},
+			fields: []string{"[0].permission", "[0].access_control", "[0].encryption"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, validateDatabaseUserSpecs(tt.engine, tt.specs), tt.fields)
+		})
+	}
+}
+
+func TestSyncDatabaseUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls []string
+	mux.HandleFunc("/v2/databases/db", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"database":{"id":"db","database_engine":"valkey","user":"default"}}`)
+	})
+	mux.HandleFunc("/v2/databases/db/users", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, `{"users":[
+				{"username":"default","password":"admin"},
+				{"username":"app","password":"old","access_control":{"acl_categories":["+@read"],"acl_channels":[],"acl_commands":[],"acl_keys":[]}},
+				{"username":"stale","password":"stale"}
+			],"meta":{"total":3,"links":{}}}`)
+			return
+		}
+
+		var req DatabaseUserCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		calls = append(calls, "create "+req.Username)
+		fmt.Fprintf(writer, `{"user":{"username":%q,"password":"generated"}}`, req.Username)
+	})
+	mux.HandleFunc("/v2/databases/db/users/", func(writer http.ResponseWriter, request *http.Request) {
+		path := strings.TrimPrefix(request.URL.Path, "/v2/databases/db/users/")
+		switch request.Method {
+		case http.MethodPut:
+			var req DatabaseUserUpdateReq
+			if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			if strings.HasSuffix(path, "/access-control") {
+				calls = append(calls, "acl "+strings.TrimSuffix(path, "/access-control"))
+			} else {
+				if len(req.Password) != databasePasswordLength {
+					t.Errorf("rotated password %q has length %d, expected %d", req.Password, len(req.Password), databasePasswordLength)
+				}
+				calls = append(calls, "rotate "+path)
+			}
+			fmt.Fprintf(writer, `{"user":{"username":%q}}`, path)
+		case http.MethodDelete:
+			calls = append(calls, "delete "+path)
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Request method = %v, expecting PUT or DELETE", request.Method)
+		}
+	})
+
+	desired := []DatabaseUserSpec{
+		{Username: "app", AccessControl: &DatabaseUserACL{ACLCategories: []string{"+@read", "+@write"}}, RotatePassword: true},
+		{Username: "worker", AccessControl: &DatabaseUserACL{ACLKeys: []string{"jobs:*"}}},
+	}
+
+	var out bytes.Buffer
+	plan, creds, err := SyncDatabaseUsers(ctx, client, "db", desired, &DatabaseUserSyncOptions{Prune: true, Output: &out})
+	if err != nil {
+		t.Fatalf("SyncDatabaseUsers returned %+v", err)
+	}
+
+	expectedCalls := []string{"create worker", "acl app", "acl worker", "rotate app", "delete stale"}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("calls = %v, expected %v", calls, expectedCalls)
+	}
+	if len(plan.Deletes) != 1 || out.String() != plan.String() {
+		t.Errorf("plan = %q, output = %q", plan.String(), out.String())
+	}
+
+	if len(creds) != 2 || creds[0].Username != "worker" || creds[0].Password != "generated" {
+		t.Fatalf("credentials = %#v, expected worker and app", creds)
+	}
+	if creds[1].Username != "app" || len(creds[1].Password) != databasePasswordLength {
+		t.Errorf("credentials of app = %s, expected the rotated password", creds[1])
+	}
+
+	printed := fmt.Sprintf("%v %+v %#v %s", creds, creds[0], creds[0], creds[0])
+	if strings.Contains(printed, "generated") || strings.Contains(printed, creds[1].Password) {
+		t.Errorf("printing credentials leaks passwords: %s", printed)
+	}
+}
+
+func TestSyncDatabaseUsersDryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/databases/db", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"database":{"id":"db","database_engine":"kafka","user":"vultradmin"}}`)
+	})
+	mux.HandleFunc("/v2/databases/db/users", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodGet)
+		}
+		fmt.Fprint(writer, `{"users":[{"username":"vultradmin","permission":"admin"}],"meta":{"total":1,"links":{}}}`)
+	})
+
+	plan, creds, err := SyncDatabaseUsers(ctx, client, "db", []DatabaseUserSpec{{Username: "app", Permission: "read"}},
+		&DatabaseUserSyncOptions{Prune: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncDatabaseUsers returned %+v", err)
+	}
+	if len(plan.Creates) != 1 || len(plan.Deletes) != 0 || creds != nil {
+		t.Errorf("plan = %q, credentials = %v, expected one create and the admin kept", plan.String(), creds)
+	}
+
+	_, _, err = SyncDatabaseUsers(ctx, client, "db", []DatabaseUserSpec{{Username: "app", AccessControl: &DatabaseUserACL{}}}, nil)
+	assertValidationFields(t, err, []string{"[0].access_control"})
+}
@@ -0,0 +1,252 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDDNSInterval   = 5 * time.Minute
+	defaultDDNSMinBackoff = 10 * time.Second
+	defaultDDNSMaxBackoff = 10 * time.Minute
+	ipDetectMaxBody       = 256
+)
+
+// ErrDDNSRecordNotFound is returned when the record managed by a DDNSUpdater does not exist
+var ErrDDNSRecordNotFound = errors.New("dynamic dns record not found")
+
+// IPDetector reports the current public address of the host
+type IPDetector interface {
+	DetectIP(ctx context.Context, ipv6 bool) (net.IP, error)
+}
+
+// IPDetectorFunc adapts a function to the IPDetector interface
+type IPDetectorFunc func(ctx context.Context, ipv6 bool) (net.IP, error)
+
+// DetectIP calls f(ctx, ipv6)
+func (f IPDetectorFunc) DetectIP(ctx context.Context, ipv6 bool) (net.IP, error) {
+	return f(ctx, ipv6)
+}
+
+// HTTPIPDetector detects the public address by asking an echo service that
+// responds with the caller's address as plain text
+type HTTPIPDetector struct {
+	IPv4URL string
+	IPv6URL string
+}
+
+// NewHTTPIPDetector returns an HTTPIPDetector using the ipify echo service
+func NewHTTPIPDetector() *HTTPIPDetector {
+	return &HTTPIPDetector{
+		IPv4URL: "https://api.ipify.org",
+		IPv6URL: "https://api6.ipify.org",
+	}
+}
+
+// DetectIP queries the echo service over the requested address family
+func (h *HTTPIPDetector) DetectIP(ctx context.Context, ipv6 bool) (net.IP, error) {
+	url, network := h.IPv4URL, "tcp4"
+	if ipv6 {
+		url, network = h.IPv6URL, "tcp6"
+	}
+
+	dialer := &net.Dialer{}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("detecting ip from %s: unexpected status %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ipDetectMaxBody))
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, fmt.Errorf("detecting ip from %s: invalid address %q", url, strings.TrimSpace(string(body)))
+	}
+	return ip, nil
+}
+
+// DDNSChange describes the outcome of checking one address family
+type DDNSChange struct {
+	Type     string
+	RecordID string
+	Previous string
+	Current  string
+	Updated  bool
+}
+
+// DDNSUpdater keeps the A and/or AAAA record of a hostname pointed at the
+// host's current public address
+type DDNSUpdater struct {
+	client *Client
+
+	Domain string
+	// Name of the record relative to Domain, "@" or "" for the apex
+	Name     string
+	IPv4     bool
+	IPv6     bool
+	Detector IPDetector
+
+	// Interval between checks, defaults to 5 minutes
+	Interval time.Duration
+	// MinBackoff and MaxBackoff bound the exponential delay after failed checks
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnUpdate and OnError are optional hooks called by Run
+	OnUpdate func(change DDNSChange)
+	OnError  func(err error)
+}
+
+// NewDDNSUpdater returns an updater for the IPv4 address of name in domain.
+// Set IPv6 to also maintain the AAAA record.
+func NewDDNSUpdater(client *Client, domain, name string, detector IPDetector) *DDNSUpdater {
+	return &DDNSUpdater{
+		client:   client,
+		Domain:   domain,
+		Name:     name,
+		IPv4:     true,
+		Detector: detector,
+	}
+}
+
+// Update runs a single check, updating each record whose address has changed
+func (u *DDNSUpdater) Update(ctx context.Context) ([]DDNSChange, error) {
+	if u.Detector == nil {
+		return nil, errors.New("dynamic dns updater has no ip detector")
+	}
+
+	records, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return u.client.DomainRecord.List(ctx, u.Domain, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []DDNSChange
+	var errs []error
+	for _, recordType := range u.recordTypes() {
+		change, err := u.updateRecord(ctx, recordType, records)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+func (u *DDNSUpdater) recordTypes() []string {
+	var types []string
+	if u.IPv4 {
+		types = append(types, RecordTypeA)
+	}
+	if u.IPv6 {
+		types = append(types, RecordTypeAAAA)
+	}
+	return types
+}
+
+func (u *DDNSUpdater) updateRecord(ctx context.Context, recordType string, records []DomainRecord) (*DDNSChange, error) {
+	ipv6 := recordType == RecordTypeAAAA
+	ip, err := u.Detector.DetectIP(ctx, ipv6)
+	if err != nil {
+		return nil, fmt.Errorf("detecting %s address: %w", recordType, err)
+	}
+	if (ip.To4() != nil) == ipv6 {
+		return nil, fmt.Errorf("detector returned %s for a %s record", ip, recordType)
+	}
+
+	name := normalizeSyncRecord(u.Domain, &DomainRecord{Name: u.Name}).Name
+
+	var record *DomainRecord
+	for idx := range records {
+		if strings.EqualFold(records[idx].Type, recordType) && strings.EqualFold(records[idx].Name, name) {
+			record = &records[idx]
+			break
+		}
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%s record %q in %s: %w", recordType, u.Name, u.Domain, ErrDDNSRecordNotFound)
+	}
+
+	change := &DDNSChange{Type: recordType, RecordID: record.ID, Previous: record.Data, Current: ip.String()}
+	if current := net.ParseIP(record.Data); current != nil && current.Equal(ip) {
+		return change, nil
+	}
+
+	if err := u.client.DomainRecord.Update(ctx, u.Domain, record.ID, &DomainRecordUpdateReq{Data: ip.String()}); err != nil {
+		return nil, fmt.Errorf("updating %s record %s: %w", recordType, record.ID, err)
+	}
+	change.Updated = true
+
+	return change, nil
+}
+
+// Run checks the records every Interval until ctx is cancelled, backing off
+// exponentially while checks fail. It returns the context's error.
+func (u *DDNSUpdater) Run(ctx context.Context) error {
+	var backoff time.Duration
+	for {
+		delay := durationOr(u.Interval, defaultDDNSInterval)
+
+		changes, err := u.Update(ctx)
+		if u.OnUpdate != nil {
+			for _, change := range changes {
+				if change.Updated {
+					u.OnUpdate(change)
+				}
+			}
+		}
+
+		if err != nil {
+			if u.OnError != nil {
+				u.OnError(err)
+			}
+			backoff = min(max(backoff*2, durationOr(u.MinBackoff, defaultDDNSMinBackoff)), durationOr(u.MaxBackoff, defaultDDNSMaxBackoff))
+			delay = backoff
+		} else {
+			backoff = 0
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func durationOr(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
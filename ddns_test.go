@@ -0,0 +1,189 @@
+package govultr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const ddnsRecordsResponse = `{"records":[
+	{"id":"apex","type":"A","name":"","data":"192.0.2.1","ttl":300},
+	{"id":"home4","type":"A","name":"home","data":"192.0.2.10","ttl":60},
+	{"id":"home6","type":"AAAA","name":"home","data":"2001:db8::10","ttl":60}
+],"meta":{"total":3,"links":{}}}`
+
+func staticDetector(v4, v6 string) IPDetector {
+	return IPDetectorFunc(func(_ context.Context, ipv6 bool) (net.IP, error) {
+		if ipv6 {
+			return net.ParseIP(v6), nil
+		}
+		return net.ParseIP(v4), nil
+	})
+}
+
+func registerDDNSRecords(t *testing.T) *[]string {
+	var mu sync.Mutex
+	updates := []string{}
+
+	mux.HandleFunc("/v2/domains/example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, ddnsRecordsResponse)
+	})
+
+	for _, id := range []string{"apex", "home4", "home6"} {
+		mux.HandleFunc("/v2/domains/example.com/records/"+id, func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method != http.MethodPatch {
+				t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodPatch)
+			}
+
+			var req DomainRecordUpdateReq
+			if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			updates = append(updates, id+" "+req.Data)
+			mu.Unlock()
+		})
+	}
+
+	return &updates
+}
+
+func TestDDNSUpdater_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	updates := registerDDNSRecords(t)
+
+	updater := NewDDNSUpdater(client, "example.com", "home.example.com.", staticDetector("192.0.2.10", "2001:db8::20"))
+	updater.IPv6 = true
+
+	changes, err := updater.Update(ctx)
+	if err != nil {
+		t.Fatalf("DDNSUpdater.Update returned %+v", err)
+	}
+
+	expected := []DDNSChange{
+		{Type: "A", RecordID: "home4", Previous: "192.0.2.10", Current: "192.0.2.10"},
+		{Type: "AAAA", RecordID: "home6", Previous: "2001:db8::10", Current: "2001:db8::20", Updated: true},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("DDNSUpdater.Update returned %+v, expected %+v", changes, expected)
+	}
+
+	if !reflect.DeepEqual(*updates, []string{"home6 2001:db8::20"}) {
+		t.Errorf("DDNSUpdater.Update sent updates %v", *updates)
+	}
+}
+
+func TestDDNSUpdater_UpdateApex(t *testing.T) {
+	setup()
+	defer teardown()
+
+	updates := registerDDNSRecords(t)
+
+	if _, err := NewDDNSUpdater(client, "example.com", "@", staticDetector("198.51.100.7", "")).Update(ctx); err != nil {
+		t.Fatalf("DDNSUpdater.Update returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(*updates, []string{"apex 198.51.100.7"}) {
+		t.Errorf("DDNSUpdater.Update sent updates %v", *updates)
+	}
+}
+
+func TestDDNSUpdater_UpdateErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	registerDDNSRecords(t)
+
+	_, err := NewDDNSUpdater(client, "example.com", "missing", staticDetector("192.0.2.10", "")).Update(ctx)
+	if !errors.Is(err, ErrDDNSRecordNotFound) {
+		t.Errorf("DDNSUpdater.Update returned %v, expected ErrDDNSRecordNotFound", err)
+	}
+
+	_, err = NewDDNSUpdater(client, "example.com", "home", staticDetector("2001:db8::1", "")).Update(ctx)
+	if err == nil {
+		t.Error("DDNSUpdater.Update expected an error for an IPv6 address on an A record")
+	}
+}
+
+func TestDDNSUpdater_Run(t *testing.T) {
+	setup()
+	defer teardown()
+
+	updates := registerDDNSRecords(t)
+
+	var mu sync.Mutex
+	calls := 0
+	detector := IPDetectorFunc(func(_ context.Context, _ bool) (net.IP, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls <= 2 {
+			return nil, errors.New("network unreachable")
+		}
+		return net.ParseIP("192.0.2.99"), nil
+	})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updater := NewDDNSUpdater(client, "example.com", "home", detector)
+	updater.Interval = time.Hour
+	updater.MinBackoff = time.Millisecond
+	updater.MaxBackoff = 5 * time.Millisecond
+
+	var errCount int
+	updater.OnError = func(error) { errCount++ }
+	updater.OnUpdate = func(change DDNSChange) {
+		if change.Current != "192.0.2.99" {
+			t.Errorf("DDNSUpdater.Run reported change %+v", change)
+		}
+		cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- updater.Run(runCtx) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("DDNSUpdater.Run returned %v, expected context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DDNSUpdater.Run did not recover from failed checks")
+	}
+
+	if errCount != 2 {
+		t.Errorf("DDNSUpdater.Run reported %d errors, expected 2", errCount)
+	}
+	if !reflect.DeepEqual(*updates, []string{"home4 192.0.2.99"}) {
+		t.Errorf("DDNSUpdater.Run sent updates %v", *updates)
+	}
+}
+
+func TestHTTPIPDetector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, "203.0.113.5\n")
+	}))
+	defer server.Close()
+
+	detector := &HTTPIPDetector{IPv4URL: server.URL}
+	ip, err := detector.DetectIP(ctx, false)
+	if err != nil {
+		t.Fatalf("HTTPIPDetector.DetectIP returned %+v", err)
+	}
+
+	if !ip.Equal(net.ParseIP("203.0.113.5")) {
+		t.Errorf("HTTPIPDetector.DetectIP returned %s, expected 203.0.113.5", ip)
+	}
+}
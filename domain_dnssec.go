@@ -0,0 +1,399 @@
+package govultr
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// DNSSEC settings accepted by DomainService.Update
+const (
+	DNSSecEnabled  = "enabled"
+	DNSSecDisabled = "disabled"
+)
+
+// DNSSEC algorithm and digest type numbers, see RFC 8624
+const (
+	DNSSecAlgRSAMD5          = 1
+	DNSSecAlgRSASHA256       = 8
+	DNSSecAlgECDSAP256SHA256 = 13
+	DNSSecAlgED25519         = 15
+
+	DSDigestSHA1   = 1
+	DSDigestSHA256 = 2
+	DSDigestSHA384 = 4
+)
+
+const (
+	dnsKeyFlagSEP      = 0x0001
+	dnsKeyFlagZone     = 0x0100
+	dnsKeyProtocol     = 3
+	dnsKeyRDataFields  = 4
+	dsRDataFields      = 4
+	dnsKeyTagFoldShift = 16
+	dnsKeyTagMask      = 0xffff
+	byteShift          = 8
+	maxDNSLabelLength  = 63
+)
+
+// ErrDNSSecNotEnabled is returned when DNSSEC keys are requested for a domain without DNSSEC
+var ErrDNSSecNotEnabled = errors.New("dnssec is not enabled for the domain")
+
+// DNSSec holds the parsed DNSSEC records for a domain
+type DNSSec struct {
+	// Records are the raw presentation-format records returned by the API
+	Records []string
+	DNSKeys []DNSKey
+	DS      []DSRecord
+	// Skipped are the DNSKEY and DS records that could not be parsed; they are
+	// still listed in Records
+	Skipped []*DNSSecRecordError
+}
+
+// DNSSecRecordError describes a DNSKEY or DS record that could not be parsed
+type DNSSecRecordError struct {
+	Record string
+	Err    error
+}
+
+func (e *DNSSecRecordError) Error() string {
+	return fmt.Sprintf("parsing %q: %v", e.Record, e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *DNSSecRecordError) Unwrap() error {
+	return e.Err
+}
+
+// DNSKey represents a DNSKEY record
+type DNSKey struct {
+	Owner     string
+	Flags     int
+	Protocol  int
+	Algorithm int
+	PublicKey string
+}
+
+// DSRecord represents a DS (delegation signer) record
+type DSRecord struct {
+	Owner      string
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+}
+
+// ParseDNSSec parses DNSKEY and DS records in presentation format. Records of
+// other types are kept in Records only. DNSKEY and DS records that cannot be parsed
+// are listed in Skipped and reported together in the returned error, while the
+// records that did parse are still returned.
+func ParseDNSSec(records []string) (*DNSSec, error) {
+	dnsSec := &DNSSec{Records: records}
+
+	for _, record := range records {
+		fields := strings.Fields(record)
+		if len(fields) == 0 {
+			continue
+		}
+
+		typeIdx := -1
+		for i, f := range fields[1:] {
+			if t := strings.ToUpper(f); t == "DNSKEY" || t == "DS" {
+				typeIdx = i + 1
+				break
+			}
+		}
+		if typeIdx == -1 {
+			continue
+		}
+
+		owner, rdata := fields[0], fields[typeIdx+1:]
+		switch strings.ToUpper(fields[typeIdx]) {
+		case "DNSKEY":
+			key, err := parseDNSKey(owner, rdata)
+			if err != nil {
+				dnsSec.Skipped = append(dnsSec.Skipped, &DNSSecRecordError{Record: record, Err: err})
+				continue
+			}
+			dnsSec.DNSKeys = append(dnsSec.DNSKeys, *key)
+		case "DS":
+			ds, err := parseDS(owner, rdata)
+			if err != nil {
+				dnsSec.Skipped = append(dnsSec.Skipped, &DNSSecRecordError{Record: record, Err: err})
+				continue
+			}
+			dnsSec.DS = append(dnsSec.DS, *ds)
+		}
+	}
+
+	errs := make([]error, len(dnsSec.Skipped))
+	for i, skipped := range dnsSec.Skipped {
+		errs[i] = skipped
+	}
+	return dnsSec, errors.Join(errs...)
+}
+
+func parseDNSKey(owner string, rdata []string) (*DNSKey, error) {
+	if len(rdata) < dnsKeyRDataFields {
+		return nil, errors.New("dnskey record needs flags, protocol, algorithm and public key")
+	}
+
+	nums, err := atoiAll(rdata[:3])
+	if err != nil {
+		return nil, err
+	}
+
+	key := &DNSKey{Owner: owner, Flags: nums[0], Protocol: nums[1], Algorithm: nums[2], PublicKey: strings.Join(rdata[3:], "")}
+	if key.Protocol != dnsKeyProtocol {
+		return nil, fmt.Errorf("dnskey protocol is %d, expected %d", key.Protocol, dnsKeyProtocol)
+	}
+	if key.Flags&dnsKeyFlagZone == 0 {
+		return nil, fmt.Errorf("dnskey flags %d do not have the zone key bit set", key.Flags)
+	}
+	if _, err := base64.StdEncoding.DecodeString(key.PublicKey); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return key, nil
+}
+
+func parseDS(owner string, rdata []string) (*DSRecord, error) {
+	if len(rdata) < dsRDataFields {
+		return nil, errors.New("ds record needs key tag, algorithm, digest type and digest")
+	}
+
+	nums, err := atoiAll(rdata[:3])
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &DSRecord{
+		Owner:      owner,
+		KeyTag:     nums[0],
+		Algorithm:  nums[1],
+		DigestType: nums[2],
+		Digest:     strings.ToUpper(strings.Join(rdata[3:], "")),
+	}
+	if _, err := hex.DecodeString(ds.Digest); err != nil {
+		return nil, fmt.Errorf("invalid digest: %w", err)
+	}
+
+	return ds, nil
+}
+
+func atoiAll(values []string) ([]int, error) {
+	nums := make([]int, len(values))
+	for i, v := range values {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+// KSK returns the key signing keys, the keys whose DS records belong at the registrar
+func (d *DNSSec) KSK() []DNSKey {
+	var keys []DNSKey
+	for _, key := range d.DNSKeys {
+		if key.IsKSK() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// DSRecords returns the DS records published by the API, or computes SHA-256
+// DS records from the key signing keys when none were returned. It returns
+// ErrDNSSecNotEnabled when there are no keys.
+func (d *DNSSec) DSRecords() ([]DSRecord, error) {
+	if len(d.DS) > 0 {
+		return d.DS, nil
+	}
+
+	var records []DSRecord
+	for _, key := range d.KSK() {
+		ds, err := key.ToDS(DSDigestSHA256)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *ds)
+	}
+
+	if len(records) == 0 {
+		return nil, ErrDNSSecNotEnabled
+	}
+	return records, nil
+}
+
+// IsKSK reports whether the key has the secure entry point flag set
+func (k *DNSKey) IsKSK() bool {
+	return k.Flags&dnsKeyFlagSEP != 0
+}
+
+// KeyTag computes the key tag as described in RFC 4034 appendix B
+func (k *DNSKey) KeyTag() (int, error) {
+	rdata, err := k.rdata()
+	if err != nil {
+		return 0, err
+	}
+
+	if k.Algorithm == DNSSecAlgRSAMD5 {
+		n := len(rdata)
+		return int(rdata[n-3])<<byteShift | int(rdata[n-2]), nil
+	}
+
+	var ac int
+	for i, b := range rdata {
+		if i%2 == 0 {
+			ac += int(b) << byteShift
+		} else {
+			ac += int(b)
+		}
+	}
+	ac += ac >> dnsKeyTagFoldShift & dnsKeyTagMask
+
+	return ac & dnsKeyTagMask, nil
+}
+
+// ToDS computes the DS record of the key with the given digest type
+func (k *DNSKey) ToDS(digestType int) (*DSRecord, error) {
+	var h hash.Hash
+	switch digestType {
+	case DSDigestSHA1:
+		h = sha1.New() //nolint:gosec
+	case DSDigestSHA256:
+		h = sha256.New()
+	case DSDigestSHA384:
+		h = sha512.New384()
+	default:
+		return nil, fmt.Errorf("unsupported ds digest type %d", digestType)
+	}
+
+	rdata, err := k.rdata()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := dnsWireName(k.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := k.KeyTag()
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write(owner)
+	h.Write(rdata)
+
+	return &DSRecord{
+		Owner:      k.Owner,
+		KeyTag:     tag,
+		Algorithm:  k.Algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+	}, nil
+}
+
+// rdata returns the wire format of the key's rdata
+func (k *DNSKey) rdata() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(key) < 3 {
+		return nil, errors.New("public key is too short")
+	}
+
+	rdata := []byte{byte(k.Flags >> byteShift), byte(k.Flags), byte(k.Protocol), byte(k.Algorithm)}
+	return append(rdata, key...), nil
+}
+
+// RData returns the record data in presentation format
+func (k *DNSKey) RData() string {
+	return fmt.Sprintf("%d %d %d %s", k.Flags, k.Protocol, k.Algorithm, k.PublicKey)
+}
+
+// String returns the record in zone file format
+func (k *DNSKey) String() string {
+	return fmt.Sprintf("%s IN DNSKEY %s", fqdn(k.Owner), k.RData())
+}
+
+// RData returns the record data in presentation format, as most registrars accept it
+func (d *DSRecord) RData() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// String returns the record in zone file format, as used for the parent zone
+func (d *DSRecord) String() string {
+	return fmt.Sprintf("%s IN DS %s", fqdn(d.Owner), d.RData())
+}
+
+// RegistrarFields returns the DS record as the labelled fields registrar forms ask for
+func (d *DSRecord) RegistrarFields() map[string]string {
+	return map[string]string{
+		"key_tag":     strconv.Itoa(d.KeyTag),
+		"algorithm":   strconv.Itoa(d.Algorithm),
+		"digest_type": strconv.Itoa(d.DigestType),
+		"digest":      d.Digest,
+	}
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// dnsWireName encodes a domain name in canonical (lower case) wire format
+func dnsWireName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > maxDNSLabelLength {
+				return nil, fmt.Errorf("invalid domain name %q", name)
+			}
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	return append(wire, 0), nil
+}
+
+// EnableDNSSec turns on DNSSEC for a domain and waits until its keys are published
+func (d *DomainServiceHandler) EnableDNSSec(ctx context.Context, domain string, wait *WaitOptions) (*DNSSec, error) {
+	if err := d.Update(ctx, domain, DNSSecEnabled); err != nil {
+		return nil, err
+	}
+
+	var dnsSec *DNSSec
+	err := waitFor(ctx, wait, fmt.Sprintf("dnssec keys for %s", domain), func(ctx context.Context) (bool, error) {
+		current, _, err := d.GetDNSSec(ctx, domain)
+		if err != nil {
+			return false, err
+		}
+
+		dnsSec = current
+		return len(current.KSK()) > 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dnsSec, nil
+}
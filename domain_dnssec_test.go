@@ -0,0 +1,154 @@
+package govultr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// RFC 4034 section 5.4 example key and its DS record
+const rfc4034DNSKey = "dskey.example.com. 86400 IN DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZ" +
+	"DRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+
+func TestDNSKey_ToDS(t *testing.T) {
+	dnsSec, err := ParseDNSSec([]string{rfc4034DNSKey})
+	if err != nil {
+		t.Fatalf("ParseDNSSec returned %+v", err)
+	}
+
+	if len(dnsSec.DNSKeys) != 1 {
+		t.Fatalf("ParseDNSSec returned %d keys, expected 1", len(dnsSec.DNSKeys))
+	}
+	key := dnsSec.DNSKeys[0]
+
+	tag, err := key.KeyTag()
+	if err != nil {
+		t.Fatalf("DNSKey.KeyTag returned %+v", err)
+	}
+	if tag != 60485 {
+		t.Errorf("DNSKey.KeyTag returned %d, expected 60485", tag)
+	}
+
+	ds, err := key.ToDS(DSDigestSHA1)
+	if err != nil {
+		t.Fatalf("DNSKey.ToDS returned %+v", err)
+	}
+
+	expected := "dskey.example.com. IN DS 60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118"
+	if ds.String() != expected {
+		t.Errorf("DSRecord.String returned %q, expected %q", ds.String(), expected)
+	}
+
+	if _, err := key.ToDS(3); err == nil {
+		t.Error("DNSKey.ToDS expected an error for an unsupported digest type")
+	}
+}
+
+func TestDNSSec_DSRecords(t *testing.T) {
+	dnsSec, err := ParseDNSSec([]string{
+		"example.com IN DNSKEY 256 3 13 oJMRESz5E4gYzS/q6XDrvU1qMPYIjCWzJaOau8XNEZeqCYKD5ar0IRd8KqXXFJkqmVfRvMGPmM1x8fGAa2XhSA==",
+		"example.com IN DNSKEY 257 3 13 kRrxANp7YTGqVbaWtMy8hhsK0jcG4ajjICZKMb4fKv79Vx/RSn76vNjzIT7/Uo0BXil01Fk8RRQc4nWZctGJBA==",
+	})
+	if err != nil {
+		t.Fatalf("ParseDNSSec returned %+v", err)
+	}
+
+	if len(dnsSec.KSK()) != 1 {
+		t.Errorf("DNSSec.KSK returned %d keys, expected 1", len(dnsSec.KSK()))
+	}
+
+	records, err := dnsSec.DSRecords()
+	if err != nil {
+		t.Fatalf("DNSSec.DSRecords returned %+v", err)
+	}
+
+	expected := []DSRecord{
+		{
+			Owner:      "example.com",
+			KeyTag:     27933,
+			Algorithm:  13,
+			DigestType: 2,
+			Digest:     "5ABDF2A9B2D87F55747C0064E52F794BADD01C72BF6DAFAFA25BFE1FE988DE42",
+		},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("DNSSec.DSRecords returned %+v, expected %+v", records, expected)
+	}
+
+	if rdata := records[0].RData(); rdata != "27933 13 2 5ABDF2A9B2D87F55747C0064E52F794BADD01C72BF6DAFAFA25BFE1FE988DE42" {
+		t.Errorf("DSRecord.RData returned %q", rdata)
+	}
+
+	fields := records[0].RegistrarFields()
+	if fields["key_tag"] != "27933" || fields["digest_type"] != "2" {
+		t.Errorf("DSRecord.RegistrarFields returned %+v", fields)
+	}
+
+	empty := &DNSSec{}
+	if _, err := empty.DSRecords(); !errors.Is(err, ErrDNSSecNotEnabled) {
+		t.Errorf("DNSSec.DSRecords returned %v, expected ErrDNSSecNotEnabled", err)
+	}
+}
+
+func TestParseDNSSec_Invalid(t *testing.T) {
+	tests := []string{
+		"example.com IN DNSKEY 257 3",
+		"example.com IN DNSKEY 257 x 13 AAAA",
+		"example.com IN DNSKEY 257 3 13 not*base64",
+		"example.com IN DNSKEY 257 2 13 AAAA",
+		"example.com IN DNSKEY 1 3 13 AAAA",
+		"example.com IN DS 27933 13 2 nothex",
+	}
+
+	for _, record := range tests {
+		dnsSec, err := ParseDNSSec([]string{rfc4034DNSKey, record})
+		if err == nil {
+			t.Errorf("ParseDNSSec(%q) expected an error", record)
+		}
+
+		var recordErr *DNSSecRecordError
+		if !errors.As(err, &recordErr) || recordErr.Record != record {
+			t.Errorf("ParseDNSSec(%q) returned %v, expected a DNSSecRecordError for the record", record, err)
+		}
+		if dnsSec == nil || len(dnsSec.DNSKeys) != 1 || len(dnsSec.Skipped) != 1 || len(dnsSec.Records) != 2 {
+			t.Errorf("ParseDNSSec(%q) returned %+v, expected the valid key and one skipped record", record, dnsSec)
+		}
+	}
+}
+
+func TestDNSDomainServiceHandler_EnableDNSSec(t *testing.T) {
+	setup()
+	defer teardown()
+
+	enabled := false
+	mux.HandleFunc("/v2/domains/example.com", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPut {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodPut)
+		}
+		enabled = true
+	})
+
+	polls := 0
+	mux.HandleFunc("/v2/domains/example.com/dnssec", func(writer http.ResponseWriter, request *http.Request) {
+		polls++
+		if !enabled || polls < 2 {
+			fmt.Fprint(writer, `{"dns_sec":[]}`)
+			return
+		}
+		fmt.Fprint(writer, `{"dns_sec":[
+			"example.com IN DNSKEY 257 3 13 kRrxANp7YTGqVbaWtMy8hhsK0jcG4ajjICZKMb4fKv79Vx/RSn76vNjzIT7/Uo0BXil01Fk8RRQc4nWZctGJBA=="
+		]}`)
+	})
+
+	dnsSec, err := client.Domain.EnableDNSSec(ctx, "example.com", &WaitOptions{Interval: time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Domain.EnableDNSSec returned %+v", err)
+	}
+
+	if len(dnsSec.KSK()) != 1 || polls != 2 {
+		t.Errorf("Domain.EnableDNSSec returned %+v after %d polls", dnsSec, polls)
+	}
+}
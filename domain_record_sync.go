@@ -0,0 +1,405 @@
+package govultr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	recordOwnerPrefix   = "_govultr-owner"
+	recordOwnerHeritage = "heritage=govultr"
+	recordDigestLength  = 8
+)
+
+// RecordSyncOptions controls how SyncRecords converges a domain
+type RecordSyncOptions struct {
+	// OwnerID enables ownership markers. Every record created by the sync gets a
+	// TXT marker naming this owner and the record, and only records carrying a
+	// marker are ever deleted, so records managed elsewhere are left alone even
+	// when they share a name and type with a desired record.
+	OwnerID string
+
+	// Prune deletes every record that is not desired when OwnerID is empty. It is
+	// ignored when OwnerID is set.
+	Prune bool
+
+	// DryRun computes and reports the plan without changing anything
+	DryRun bool
+
+	// Output receives the human readable plan before it is applied
+	Output io.Writer
+}
+
+// RecordUpdate is a change to the TTL or priority of an existing record
+type RecordUpdate struct {
+	Current DomainRecord
+	Desired DomainRecord
+}
+
+// RecordSyncPlan lists the changes needed to converge a domain to its desired records
+type RecordSyncPlan struct {
+	Domain  string
+	Creates []DomainRecord
+	Updates []RecordUpdate
+	Deletes []DomainRecord
+}
+
+// Empty reports whether the domain already matches the desired records
+func (r *RecordSyncPlan) Empty() bool {
+	return len(r.Creates) == 0 && len(r.Updates) == 0 && len(r.Deletes) == 0
+}
+
+// String renders the plan for people to review
+func (r *RecordSyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for %s: %d to create, %d to update, %d to delete\n",
+		r.Domain, len(r.Creates), len(r.Updates), len(r.Deletes))
+
+	for idx := range r.Creates {
+		fmt.Fprintf(&b, "  + %s\n", describeRecord(&r.Creates[idx]))
+	}
+	for idx := range r.Updates {
+		u := &r.Updates[idx]
+		fmt.Fprintf(&b, "  ~ %s (ttl %d -> %d, priority %d -> %d)\n",
+			describeRecord(&u.Current), u.Current.TTL, u.Desired.TTL, u.Current.Priority, u.Desired.Priority)
+	}
+	for idx := range r.Deletes {
+		fmt.Fprintf(&b, "  - %s\n", describeRecord(&r.Deletes[idx]))
+	}
+
+	return b.String()
+}
+
+func describeRecord(r *DomainRecord) string {
+	name := r.Name
+	if name == "" {
+		name = "@"
+	}
+	return fmt.Sprintf("%s %s %s", name, r.Type, r.Data)
+}
+
+// SyncRecords converges the records of domain to desired. Records are matched by name,
+// type and data; matches whose TTL or priority differ are updated, missing records are
+// created and surplus records are deleted subject to the ownership rules in opts. If
+// applying the plan fails part way the changes already made are rolled back.
+func (d *DomainRecordsServiceHandler) SyncRecords(ctx context.Context, domain string, desired []DomainRecord, opts *RecordSyncOptions) (*RecordSyncPlan, error) { //nolint:lll
+	if opts == nil {
+		opts = &RecordSyncOptions{}
+	}
+
+	current, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return d.List(ctx, domain, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planRecordSync(domain, current, desired, opts)
+
+	if opts.Output != nil {
+		if _, err := io.WriteString(opts.Output, plan.String()); err != nil {
+			return plan, err
+		}
+	}
+
+	if opts.DryRun || plan.Empty() {
+		return plan, nil
+	}
+
+	return plan, d.applyRecordSync(ctx, plan)
+}
+
+type recordKey struct {
+	name, recordType, data string
+}
+
+func planRecordSync(domain string, current, desired []DomainRecord, opts *RecordSyncOptions) *RecordSyncPlan {
+	plan := &RecordSyncPlan{Domain: domain}
+
+	markers := make(map[recordKey]DomainRecord)
+	existing := make(map[recordKey]DomainRecord)
+	for idx := range current {
+		record := current[idx]
+		if owner, key, ok := parseOwnerMarker(&record); ok {
+			if owner == opts.OwnerID {
+				markers[key] = record
+			}
+			continue
+		}
+		existing[syncRecordKey(domain, &record)] = record
+	}
+
+	wanted := make(map[recordKey]bool)
+	for idx := range desired {
+		record := normalizeSyncRecord(domain, &desired[idx])
+		key := syncRecordKey(domain, &record)
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+
+		cur, ok := existing[key]
+		switch {
+		case !ok:
+			plan.Creates = append(plan.Creates, record)
+			if _, marked := markers[ownerMarkerKey(key)]; opts.OwnerID != "" && !marked {
+				plan.Creates = append(plan.Creates, ownerMarker(opts.OwnerID, &record, key))
+			}
+		case recordNeedsUpdate(&cur, &record):
+			plan.Updates = append(plan.Updates, RecordUpdate{Current: cur, Desired: record})
+		}
+	}
+
+	for key := range existing {
+		if wanted[key] {
+			continue
+		}
+		_, owned := markers[ownerMarkerKey(key)]
+		if (opts.OwnerID == "" && opts.Prune) || owned {
+			plan.Deletes = append(plan.Deletes, existing[key])
+		}
+	}
+
+	// markers go with the record they cover
+	if opts.OwnerID != "" {
+		for key := range wanted {
+			delete(markers, ownerMarkerKey(key))
+		}
+		for _, marker := range markers {
+			plan.Deletes = append(plan.Deletes, marker)
+		}
+	}
+
+	sortRecords(plan.Creates)
+	sortRecords(plan.Deletes)
+
+	return plan
+}
+
+func recordNeedsUpdate(current, desired *DomainRecord) bool {
+	if desired.TTL != 0 && desired.TTL != current.TTL {
+		return true
+	}
+	return (desired.Type == RecordTypeMX || desired.Type == RecordTypeSRV) && desired.Priority != current.Priority
+}
+
+func sortRecords(records []DomainRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].Data < records[j].Data
+	})
+}
+
+// normalizeSyncRecord puts a desired record into the form the API returns it in
+func normalizeSyncRecord(domain string, record *DomainRecord) DomainRecord {
+	normalized := *record
+	normalized.ID = ""
+	normalized.Type = strings.ToUpper(record.Type)
+
+	name := strings.TrimSuffix(strings.ToLower(record.Name), ".")
+	if name == "@" {
+		name = ""
+	}
+	if relative, ok := zoneRelativeName(name, domain); ok && strings.HasSuffix(record.Name, ".") {
+		name = relative
+	}
+	normalized.Name = name
+
+	switch normalized.Type {
+	case RecordTypeCNAME, RecordTypeNS, RecordTypeMX:
+		normalized.Data = strings.TrimSuffix(strings.ToLower(record.Data), ".")
+	case RecordTypeTXT:
+		if !strings.HasPrefix(record.Data, `"`) {
+			normalized.Data = quoteTXT([]string{record.Data})
+		}
+	}
+
+	return normalized
+}
+
+func syncRecordKey(domain string, record *DomainRecord) recordKey {
+	normalized := normalizeSyncRecord(domain, record)
+	data := normalized.Data
+	if normalized.Type == RecordTypeSRV {
+		data = strings.TrimSuffix(strings.ToLower(data), ".")
+	}
+	return recordKey{name: normalized.Name, recordType: normalized.Type, data: data}
+}
+
+// ownerMarker returns the TXT record marking record, whose sync key is key, as created
+// by owner. The marker identifies the record by name, type and a digest of its data.
+func ownerMarker(owner string, record *DomainRecord, key recordKey) DomainRecord {
+	name := recordOwnerPrefix
+	if record.Name != "" {
+		name += "." + strings.ReplaceAll(record.Name, "*", "_wildcard")
+	}
+
+	data := fmt.Sprintf(`"%s,owner=%s,type=%s,name=%s,data=%s"`,
+		recordOwnerHeritage, owner, record.Type, record.Name, ownerMarkerKey(key).data)
+	return DomainRecord{Name: name, Type: RecordTypeTXT, Data: data}
+}
+
+// ownerMarkerKey returns the key a marker for the record with the sync key key is stored under
+func ownerMarkerKey(key recordKey) recordKey {
+	digest := sha256.Sum256([]byte(key.data))
+	return recordKey{name: key.name, recordType: key.recordType, data: hex.EncodeToString(digest[:recordDigestLength])}
+}
+
+func parseOwnerMarker(record *DomainRecord) (owner string, key recordKey, ok bool) {
+	if record.Type != RecordTypeTXT {
+		return "", recordKey{}, false
+	}
+
+	data := strings.Trim(record.Data, `"`)
+	if !strings.HasPrefix(data, recordOwnerHeritage+",") {
+		return "", recordKey{}, false
+	}
+
+	for _, field := range strings.Split(data, ",")[1:] {
+		name, value, _ := strings.Cut(field, "=")
+		switch name {
+		case "owner":
+			owner = value
+		case "name":
+			key.name = value
+		case "type":
+			key.recordType = value
+		case "data":
+			key.data = value
+		}
+	}
+
+	return owner, key, owner != "" && key.recordType != "" && key.data != ""
+}
+
+type appliedRecordChange struct {
+	created  *DomainRecord
+	updated  *RecordUpdate
+	deleted  *DomainRecord
+	recordID string
+}
+
+// applyRecordSync performs creates before updates and deletes so that names are never
+// left without records, and undoes the applied changes if a later one fails. Records
+// that cannot coexist with a create at the same name, such as an A record replaced
+// by a CNAME, are deleted first.
+func (d *DomainRecordsServiceHandler) applyRecordSync(ctx context.Context, plan *RecordSyncPlan) error {
+	var applied []appliedRecordChange
+
+	fail := func(err error) error {
+		if rbErr := d.rollbackRecordSync(ctx, plan.Domain, applied); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	deleteRecords := func(records []DomainRecord) error {
+		for idx := range records {
+			record := &records[idx]
+			if err := d.Delete(ctx, plan.Domain, record.ID); err != nil {
+				return fail(fmt.Errorf("deleting %s: %w", describeRecord(record), err))
+			}
+			applied = append(applied, appliedRecordChange{deleted: record})
+		}
+		return nil
+	}
+
+	conflicting, deletes := splitConflictingDeletes(plan)
+	if err := deleteRecords(conflicting); err != nil {
+		return err
+	}
+
+	for idx := range plan.Creates {
+		record := &plan.Creates[idx]
+		created, _, err := d.Create(ctx, plan.Domain, recordCreateReq(record))
+		if err != nil {
+			return fail(fmt.Errorf("creating %s: %w", describeRecord(record), err))
+		}
+		applied = append(applied, appliedRecordChange{created: record, recordID: created.ID})
+	}
+
+	for idx := range plan.Updates {
+		update := &plan.Updates[idx]
+		if err := d.Update(ctx, plan.Domain, update.Current.ID, recordUpdateReq(&update.Desired)); err != nil {
+			return fail(fmt.Errorf("updating %s: %w", describeRecord(&update.Current), err))
+		}
+		applied = append(applied, appliedRecordChange{updated: update})
+	}
+
+	return deleteRecords(deletes)
+}
+
+// splitConflictingDeletes separates the deletes whose type conflicts with a create at the
+// same name from the rest. A CNAME cannot share its name with a record of another type.
+func splitConflictingDeletes(plan *RecordSyncPlan) (conflicting, rest []DomainRecord) {
+	for idx := range plan.Deletes {
+		record := plan.Deletes[idx]
+		conflict := false
+		for i := range plan.Creates {
+			create := &plan.Creates[i]
+			if create.Name == record.Name && create.Type != record.Type &&
+				(create.Type == RecordTypeCNAME || record.Type == RecordTypeCNAME) {
+				conflict = true
+				break
+			}
+		}
+
+		if conflict {
+			conflicting = append(conflicting, record)
+		} else {
+			rest = append(rest, record)
+		}
+	}
+
+	return conflicting, rest
+}
+
+func (d *DomainRecordsServiceHandler) rollbackRecordSync(ctx context.Context, domain string, applied []appliedRecordChange) error {
+	var errs []error
+
+	for idx := len(applied) - 1; idx >= 0; idx-- {
+		change := applied[idx]
+		var err error
+		switch {
+		case change.created != nil:
+			err = d.Delete(ctx, domain, change.recordID)
+		case change.updated != nil:
+			err = d.Update(ctx, domain, change.updated.Current.ID, recordUpdateReq(&change.updated.Current))
+		case change.deleted != nil:
+			_, _, err = d.Create(ctx, domain, recordCreateReq(change.deleted))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func recordCreateReq(record *DomainRecord) *DomainRecordCreateReq {
+	req := &DomainRecordCreateReq{Name: record.Name, Type: record.Type, Data: record.Data, TTL: record.TTL}
+	if record.Type == RecordTypeMX || record.Type == RecordTypeSRV {
+		req.Priority = IntToIntPtr(record.Priority)
+	}
+	return req
+}
+
+func recordUpdateReq(record *DomainRecord) *DomainRecordUpdateReq {
+	req := &DomainRecordUpdateReq{Name: StringToStringPtr(record.Name), Type: record.Type, Data: record.Data, TTL: record.TTL}
+	if record.Type == RecordTypeMX || record.Type == RecordTypeSRV {
+		req.Priority = IntToIntPtr(record.Priority)
+	}
+	return req
+}
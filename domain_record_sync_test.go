@@ -0,0 +1,224 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const syncRecordsResponse = `{"records":[
+	{"id":"ns1","type":"NS","name":"","data":"ns1.vultr.com","ttl":3600},
+	{"id":"www","type":"A","name":"www","data":"192.0.2.1","ttl":300},
+	{"id":"old","type":"A","name":"old","data":"192.0.2.9","ttl":300},
+	{"id":"mx","type":"MX","name":"","data":"mail.example.com","priority":10,"ttl":300},
+	{"id":"marker-old","type":"TXT","name":"_govultr-owner.old","data":"\"heritage=govultr,owner=ci,type=A,name=old,data=d27fb1b45c2670fa\"","ttl":300},
+	{"id":"marker-other","type":"TXT","name":"_govultr-owner.legacy","data":"\"heritage=govultr,owner=someone-else,type=A,name=legacy,data=37dad677cf0b3997\"","ttl":300},
+	{"id":"legacy","type":"A","name":"legacy","data":"192.0.2.7","ttl":300}
+],"meta":{"total":7,"links":{}}}`
+
+var syncDesiredRecords = []DomainRecord{
+	{Name: "www.example.com.", Type: "A", Data: "192.0.2.1", TTL: 300},
+	{Name: "@", Type: "MX", Data: "mail.example.com.", Priority: 20, TTL: 300},
+	{Name: "api", Type: "a", Data: "192.0.2.2", TTL: 120},
+}
+
+type recordSyncRecorder struct {
+	mu      sync.Mutex
+	calls   []string
+	failOn  string
+	created int
+}
+
+func (r *recordSyncRecorder) record(call string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	return call == r.failOn
+}
+
+func (r *recordSyncRecorder) register(t *testing.T) {
+	mux.HandleFunc("/v2/domains/example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, syncRecordsResponse)
+			return
+		}
+
+		var req DomainRecordCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if r.record(fmt.Sprintf("create %s %s", req.Name, req.Type)) {
+			http.Error(writer, `{"error":"boom"}`, http.StatusBadRequest)
+			return
+		}
+		r.created++
+		fmt.Fprintf(writer, `{"record":{"id":"new-%d"}}`, r.created)
+	})
+
+	mux.HandleFunc("/v2/domains/example.com/records/", func(writer http.ResponseWriter, request *http.Request) {
+		id := strings.TrimPrefix(request.URL.Path, "/v2/domains/example.com/records/")
+		if r.record(fmt.Sprintf("%s %s", strings.ToLower(request.Method), id)) {
+			http.Error(writer, `{"error":"boom"}`, http.StatusBadRequest)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func TestDomainRecordsServiceHandler_SyncRecordsPlan(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordSyncRecorder{}
+	recorder.register(t)
+
+	var output bytes.Buffer
+	plan, err := client.DomainRecord.SyncRecords(ctx, "example.com", syncDesiredRecords, &RecordSyncOptions{OwnerID: "ci", DryRun: true, Output: &output})
+	if err != nil {
+		t.Fatalf("DomainRecord.SyncRecords returned %+v", err)
+	}
+
+	if len(recorder.calls) != 0 {
+		t.Errorf("DomainRecord.SyncRecords made changes during a dry run: %v", recorder.calls)
+	}
+
+	var creates []string
+	for _, r := range plan.Creates {
+		creates = append(creates, describeRecord(&r))
+	}
+	// www and the MX record already existed, so the sync does not claim them
+	expectedCreates := []string{
+		`_govultr-owner.api TXT "heritage=govultr,owner=ci,type=A,name=api,data=9a6b293639db1e58"`,
+		"api A 192.0.2.2",
+	}
+	if !reflect.DeepEqual(creates, expectedCreates) {
+		t.Errorf("plan creates returned %q, expected %q", creates, expectedCreates)
+	}
+
+	if len(plan.Updates) != 1 || plan.Updates[0].Current.ID != "mx" || plan.Updates[0].Desired.Priority != 20 {
+		t.Errorf("plan updates returned %+v", plan.Updates)
+	}
+
+	var deletes []string
+	for _, r := range plan.Deletes {
+		deletes = append(deletes, r.ID)
+	}
+	sort.Strings(deletes)
+	if !reflect.DeepEqual(deletes, []string{"marker-old", "old"}) {
+		t.Errorf("plan deletes returned %v, expected only the owned record and its marker", deletes)
+	}
+
+	if !strings.Contains(output.String(), "Plan for example.com: 2 to create, 1 to update, 2 to delete") {
+		t.Errorf("plan output returned %s", output.String())
+	}
+}
+
+func TestPlanRecordSync_UnmanagedRecords(t *testing.T) {
+	opts := &RecordSyncOptions{OwnerID: "ci"}
+	current := []DomainRecord{{ID: "manual", Name: "www", Type: "A", Data: "192.0.2.1", TTL: 300}}
+	desired := []DomainRecord{{Name: "www", Type: "A", Data: "192.0.2.5", TTL: 300}}
+
+	first := planRecordSync("example.com", current, desired, opts)
+	if len(first.Creates) != 2 || len(first.Deletes) != 0 {
+		t.Fatalf("first plan returned %s", first)
+	}
+	for idx := range first.Creates {
+		record := first.Creates[idx]
+		record.ID = fmt.Sprintf("new-%d", idx)
+		current = append(current, record)
+	}
+
+	second := planRecordSync("example.com", current, desired, opts)
+	if !second.Empty() {
+		t.Errorf("second plan returned %s, expected the unmanaged record to be left alone", second)
+	}
+
+	// dropping the desired record deletes only the record the sync created and its marker
+	third := planRecordSync("example.com", current, nil, opts)
+	var deletes []string
+	for idx := range third.Deletes {
+		deletes = append(deletes, third.Deletes[idx].ID)
+	}
+	sort.Strings(deletes)
+	if !reflect.DeepEqual(deletes, []string{"new-0", "new-1"}) {
+		t.Errorf("third plan deletes %v, expected the created record and its marker", deletes)
+	}
+}
+
+func TestDomainRecordsServiceHandler_SyncRecordsPrune(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordSyncRecorder{}
+	recorder.register(t)
+
+	plan, err := client.DomainRecord.SyncRecords(ctx, "example.com", syncDesiredRecords, &RecordSyncOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("DomainRecord.SyncRecords returned %+v", err)
+	}
+
+	if len(plan.Creates) != 1 || len(plan.Updates) != 1 || len(plan.Deletes) != 3 {
+		t.Errorf("plan returned %s", plan)
+	}
+
+	expected := []string{"create api A", "patch mx", "delete ns1", "delete legacy", "delete old"}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("DomainRecord.SyncRecords made calls %v, expected %v", recorder.calls, expected)
+	}
+}
+
+func TestDomainRecordsServiceHandler_SyncRecordsRollback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordSyncRecorder{failOn: "delete old"}
+	recorder.register(t)
+
+	_, err := client.DomainRecord.SyncRecords(ctx, "example.com", syncDesiredRecords, &RecordSyncOptions{Prune: true})
+	if err == nil {
+		t.Fatalf("DomainRecord.SyncRecords expected an error")
+	}
+
+	expected := []string{
+		"create api A", "patch mx", "delete ns1", "delete legacy", "delete old",
+		"create legacy A", "create  NS", "patch mx", "delete new-1",
+	}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("DomainRecord.SyncRecords made calls %v, expected %v", recorder.calls, expected)
+	}
+}
+
+func TestDomainRecordsServiceHandler_SyncRecordsTypeChange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &recordSyncRecorder{}
+	recorder.register(t)
+
+	desired := append([]DomainRecord{{Name: "www", Type: "CNAME", Data: "example.com", TTL: 300}}, syncDesiredRecords[1:]...)
+	if _, err := client.DomainRecord.SyncRecords(ctx, "example.com", desired, &RecordSyncOptions{Prune: true}); err != nil {
+		t.Fatalf("DomainRecord.SyncRecords returned %+v", err)
+	}
+
+	// the A record at www is removed before the CNAME replacing it is created
+	expected := []string{"delete www", "create api A", "create www CNAME", "patch mx", "delete ns1", "delete legacy", "delete old"}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("DomainRecord.SyncRecords made calls %v, expected %v", recorder.calls, expected)
+	}
+
+	recorder.calls, recorder.failOn = nil, "create www CNAME"
+	if _, err := client.DomainRecord.SyncRecords(ctx, "example.com", desired, &RecordSyncOptions{Prune: true}); err == nil {
+		t.Fatalf("DomainRecord.SyncRecords expected an error")
+	}
+
+	expected = []string{"delete www", "create api A", "create www CNAME", "delete new-3", "create www A"}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("DomainRecord.SyncRecords made calls %v, expected %v", recorder.calls, expected)
+	}
+}
@@ -0,0 +1,404 @@
+package govultr
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxTXTStringLength = 255
+	maxDNSNameLength   = 253
+	maxUint16          = 65535
+	maxCAAFlags        = 255
+	srvDataFields      = 3
+	caaDataFields      = 3
+	sshfpDataFields    = 3
+
+	// RFC 1035 section 5.1 character-string escapes
+	charStringEscapeDigits = 3
+	charStringMaxByte      = 255
+	charStringFirstPrint   = 0x20
+	charStringLastPrint    = 0x7e
+)
+
+// NewAddressRecord returns an A or AAAA record request depending on the address family of ip
+func NewAddressRecord(name string, ip net.IP, ttl int) *DomainRecordCreateReq {
+	recordType := RecordTypeAAAA
+	if ip.To4() != nil {
+		recordType = RecordTypeA
+	}
+	return newRecordReq(name, recordType, ip.String(), ttl, nil)
+}
+
+// NewCNAMERecord returns a CNAME record request pointing name at target
+func NewCNAMERecord(name, target string, ttl int) *DomainRecordCreateReq {
+	return newRecordReq(name, RecordTypeCNAME, target, ttl, nil)
+}
+
+// NewMXRecord returns an MX record request for the given mail server
+func NewMXRecord(name, mailServer string, priority, ttl int) *DomainRecordCreateReq {
+	return newRecordReq(name, RecordTypeMX, mailServer, ttl, IntToIntPtr(priority))
+}
+
+// NewSRVRecord returns an SRV record request. name is the service and protocol
+// labels relative to the domain, such as "_sip._tcp".
+func NewSRVRecord(name string, priority, weight, port int, target string, ttl int) *DomainRecordCreateReq {
+	return newRecordReq(name, RecordTypeSRV, fmt.Sprintf("%d %d %s", weight, port, target), ttl, IntToIntPtr(priority))
+}
+
+// NewTXTRecord returns a TXT record request, splitting value into 255 byte strings as needed
+func NewTXTRecord(name, value string, ttl int) *DomainRecordCreateReq {
+	return newRecordReq(name, RecordTypeTXT, quoteTXT(splitTXT(value)), ttl, nil)
+}
+
+// NewCAARecord returns a CAA record request such as 0 issue "letsencrypt.org"
+func NewCAARecord(name string, flags int, tag, value string, ttl int) *DomainRecordCreateReq {
+	return newRecordReq(name, RecordTypeCAA, fmt.Sprintf("%d %s %s", flags, tag, quoteCharString(value)), ttl, nil)
+}
+
+func newRecordReq(name, recordType, data string, ttl int, priority *int) *DomainRecordCreateReq {
+	req := &DomainRecordCreateReq{Name: name, Type: recordType, Data: data, TTL: ttl, Priority: priority}
+	req.Normalize()
+	return req
+}
+
+// Normalize rewrites the request into the canonical form for its record type:
+// upper case type, compressed IP addresses, lower case host names without the
+// trailing dot, and TXT data quoted and split into 255 byte strings. Data that
+// cannot be parsed is left unchanged for Validate to report.
+func (d *DomainRecordCreateReq) Normalize() {
+	d.Type = strings.ToUpper(strings.TrimSpace(d.Type))
+	if d.Name == "@" {
+		d.Name = ""
+	}
+
+	data := strings.TrimSpace(d.Data)
+	switch d.Type {
+	case RecordTypeA, RecordTypeAAAA:
+		if ip := net.ParseIP(data); ip != nil {
+			data = ip.String()
+		}
+	case RecordTypeCNAME, RecordTypeNS, RecordTypeMX:
+		data = normalizeHostname(data)
+	case RecordTypeSRV:
+		if fields := strings.Fields(data); len(fields) == srvDataFields {
+			fields[2] = normalizeHostname(fields[2])
+			data = strings.Join(fields, " ")
+		}
+	case RecordTypeCAA:
+		if flags, tag, value, err := parseCAAData(data); err == nil {
+			data = fmt.Sprintf("%d %s %s", flags, strings.ToLower(tag), quoteCharString(value))
+		}
+	case RecordTypeTXT:
+		if strs, err := parseTXTData(data); err == nil {
+			var split []string
+			for _, s := range strs {
+				split = append(split, splitTXT(s)...)
+			}
+			data = quoteTXT(split)
+		}
+	case RecordTypeSSHFP:
+		data = strings.Join(strings.Fields(data), " ")
+	}
+	d.Data = data
+}
+
+func normalizeHostname(host string) string {
+	if host == "." {
+		return host
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// validateRecordData adds the type specific problems with a record's name, data and priority
+func validateRecordData(v *validation, recordType, name, data string, priority *int) {
+	if (recordType == RecordTypeMX || recordType == RecordTypeSRV) && priority == nil {
+		v.addf("priority", "is required for %s records", recordType)
+	} else if priority != nil && (*priority < 0 || *priority > maxUint16) {
+		v.addf("priority", "must be between 0 and %d", maxUint16)
+	}
+
+	if data == "" {
+		return
+	}
+
+	var err error
+	switch recordType {
+	case RecordTypeA:
+		if ip := net.ParseIP(data); ip == nil || ip.To4() == nil {
+			err = fmt.Errorf("%q is not an IPv4 address", data)
+		}
+	case RecordTypeAAAA:
+		if ip := net.ParseIP(data); ip == nil || ip.To4() != nil {
+			err = fmt.Errorf("%q is not an IPv6 address", data)
+		}
+	case RecordTypeCNAME:
+		if name == "" || name == "@" {
+			v.addf("name", "CNAME records cannot be created at the zone apex")
+		}
+		err = validateHostname(data)
+	case RecordTypeNS, RecordTypeMX:
+		err = validateHostname(data)
+	case RecordTypeSRV:
+		err = validateSRV(v, name, data)
+	case RecordTypeCAA:
+		_, _, _, err = parseCAAData(data)
+	case RecordTypeTXT:
+		err = validateTXT(data)
+	case RecordTypeSSHFP:
+		err = validateSSHFP(data)
+	}
+
+	if err != nil {
+		v.addf("data", "%s", err)
+	}
+}
+
+// validateHostname checks that host is a syntactically valid domain name
+func validateHostname(host string) error {
+	name := strings.TrimSuffix(host, ".")
+	if name == "" || len(name) > maxDNSNameLength {
+		return fmt.Errorf("%q is not a valid host name", host)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > maxDNSLabelLength || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("%q is not a valid host name", host)
+		}
+		for _, c := range label {
+			if !isHostnameRune(c) {
+				return fmt.Errorf("%q is not a valid host name", host)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isHostnameRune(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+func validateSRV(v *validation, name, data string) error {
+	if labels := strings.Split(name, "."); len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		v.addf("name", "SRV records must be named _service._proto")
+	}
+
+	fields := strings.Fields(data)
+	if len(fields) != srvDataFields {
+		return fmt.Errorf("SRV data must be \"weight port target\"")
+	}
+
+	for i, field := range fields[:2] {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || n > maxUint16 {
+			return fmt.Errorf("SRV %s must be a number between 0 and %d", []string{"weight", "port"}[i], maxUint16)
+		}
+	}
+
+	if fields[2] == "." {
+		return nil
+	}
+	return validateHostname(fields[2])
+}
+
+// parseCAAData splits CAA data of the form: flags tag "value"
+func parseCAAData(data string) (flags int, tag, value string, err error) {
+	parts := strings.SplitN(data, " ", caaDataFields)
+	if len(parts) != caaDataFields {
+		return 0, "", "", fmt.Errorf("CAA data must be \"flags tag value\"")
+	}
+
+	flags, err = strconv.Atoi(parts[0])
+	if err != nil || flags < 0 || flags > maxCAAFlags {
+		return 0, "", "", fmt.Errorf("CAA flags must be a number between 0 and %d", maxCAAFlags)
+	}
+
+	tag = parts[1]
+	if tag == "" {
+		return 0, "", "", fmt.Errorf("CAA tag is required")
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return 0, "", "", fmt.Errorf("CAA tag %q must be alphanumeric", tag)
+		}
+	}
+
+	value = strings.TrimSpace(parts[2])
+	if strings.HasPrefix(value, `"`) {
+		value, err = unquoteCharString(value)
+	} else {
+		value, err = unescapeCharString(value)
+	}
+	if err != nil {
+		return 0, "", "", fmt.Errorf("CAA value is not a valid character string: %w", err)
+	}
+
+	return flags, tag, value, nil
+}
+
+// parseTXTData returns the character strings of TXT data. Unquoted data is a single string.
+func parseTXTData(data string) ([]string, error) {
+	if !strings.HasPrefix(data, `"`) {
+		return []string{data}, nil
+	}
+
+	var strs []string
+	rest := data
+	for rest != "" {
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) {
+			return nil, fmt.Errorf("unterminated quoted string in TXT data")
+		}
+
+		s, err := unquoteCharString(rest[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted string in TXT data: %w", err)
+		}
+		strs = append(strs, s)
+
+		rest = strings.TrimLeft(rest[end+1:], " ")
+		if rest != "" && !strings.HasPrefix(rest, `"`) {
+			return nil, fmt.Errorf("TXT data mixes quoted and unquoted strings")
+		}
+	}
+
+	return strs, nil
+}
+
+// quoteCharString quotes s as an RFC 1035 character string. Quotes and
+// backslashes are escaped as \X, and bytes outside printable ASCII as \DDD
+// with a three digit decimal value.
+func quoteCharString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < charStringFirstPrint || c > charStringLastPrint:
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquoteCharString reverses quoteCharString, accepting any escape allowed by RFC 1035
+func unquoteCharString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("character string %s is not quoted", s)
+	}
+	inner := s[1 : len(s)-1]
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' {
+			i++
+		} else if inner[i] == '"' {
+			return "", fmt.Errorf("character string %s contains an unescaped quote", s)
+		}
+	}
+	return unescapeCharString(inner)
+}
+
+// unescapeCharString decodes the escapes of RFC 1035 section 5.1: \DDD is the
+// byte with decimal value DDD and \X is the character X.
+func unescapeCharString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("character string %s ends with a backslash", s)
+		}
+		if !isDigit(s[i]) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+charStringEscapeDigits > len(s) {
+			return "", fmt.Errorf("character string %s has a short \\DDD escape", s)
+		}
+		n, err := strconv.Atoi(s[i : i+charStringEscapeDigits])
+		if err != nil || n > charStringMaxByte {
+			return "", fmt.Errorf("character string %s has an invalid \\DDD escape", s)
+		}
+		b.WriteByte(byte(n))
+		i += charStringEscapeDigits - 1
+	}
+	return b.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitTXT splits a TXT value into strings of at most 255 bytes
+func splitTXT(value string) []string {
+	if value == "" {
+		return []string{""}
+	}
+
+	var strs []string
+	for len(value) > maxTXTStringLength {
+		strs = append(strs, value[:maxTXTStringLength])
+		value = value[maxTXTStringLength:]
+	}
+	return append(strs, value)
+}
+
+func validateTXT(data string) error {
+	strs, err := parseTXTData(data)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(data, `"`) {
+		// unquoted data is split by Normalize
+		return nil
+	}
+
+	for _, s := range strs {
+		if len(s) > maxTXTStringLength {
+			return fmt.Errorf("TXT strings cannot exceed %d bytes, use Normalize to split them", maxTXTStringLength)
+		}
+	}
+	return nil
+}
+
+func validateSSHFP(data string) error {
+	fields := strings.Fields(data)
+	if len(fields) != sshfpDataFields {
+		return fmt.Errorf("SSHFP data must be \"algorithm type fingerprint\"")
+	}
+
+	if _, err := atoiAll(fields[:2]); err != nil {
+		return fmt.Errorf("SSHFP algorithm and type must be numbers")
+	}
+
+	for _, c := range fields[2] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return fmt.Errorf("SSHFP fingerprint must be hexadecimal")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package govultr
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordConstructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *DomainRecordCreateReq
+		expected *DomainRecordCreateReq
+	}{
+		{
+			name:     "ipv4",
+			req:      NewAddressRecord("www", net.ParseIP("192.0.2.1"), 300),
+			expected: &DomainRecordCreateReq{Name: "www", Type: "A", Data: "192.0.2.1", TTL: 300},
+		},
+		{
+			name:     "ipv6",
+			req:      NewAddressRecord("www", net.ParseIP("2001:DB8:0:0::1"), 300),
+			expected: &DomainRecordCreateReq{Name: "www", Type: "AAAA", Data: "2001:db8::1", TTL: 300},
+		},
+		{
+			name:     "cname",
+			req:      NewCNAMERecord("docs", "Pages.Example.NET.", 0),
+			expected: &DomainRecordCreateReq{Name: "docs", Type: "CNAME", Data: "pages.example.net"},
+		},
+		{
+			name:     "mx",
+			req:      NewMXRecord("@", "mail.example.com.", 10, 3600),
+			expected: &DomainRecordCreateReq{Name: "", Type: "MX", Data: "mail.example.com", TTL: 3600, Priority: IntToIntPtr(10)},
+		},
+		{
+			name:     "srv",
+			req:      NewSRVRecord("_sip._tcp", 10, 60, 5060, "SIP.example.com.", 300),
+			expected: &DomainRecordCreateReq{Name: "_sip._tcp", Type: "SRV", Data: "60 5060 sip.example.com", TTL: 300, Priority: IntToIntPtr(10)},
+		},
+		{
+			name:     "caa",
+			req:      NewCAARecord("", 0, "Issue", "letsencrypt.org", 300),
+			expected: &DomainRecordCreateReq{Name: "", Type: "CAA", Data: `0 issue "letsencrypt.org"`, TTL: 300},
+		},
+		{
+			name:     "txt",
+			req:      NewTXTRecord("", "v=spf1 -all", 300),
+			expected: &DomainRecordCreateReq{Name: "", Type: "TXT", Data: `"v=spf1 -all"`, TTL: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.req, tt.expected) {
+				t.Errorf("constructor returned %+v, expected %+v", tt.req, tt.expected)
+			}
+			if err := tt.req.Validate(); err != nil {
+				t.Errorf("constructor returned an invalid request: %v", err)
+			}
+		})
+	}
+}
+
+func TestDomainRecordCreateReq_NormalizeTXT(t *testing.T) {
+	key := strings.Repeat("k", 300)
+	req := &DomainRecordCreateReq{Name: "mail._domainkey", Type: "txt", Data: "v=DKIM1; p=" + key}
+	req.Normalize()
+
+	value := "v=DKIM1; p=" + key
+	expected := `"` + value[:255] + `" "` + value[255:] + `"`
+	if req.Type != "TXT" || req.Data != expected {
+		t.Errorf("DomainRecordCreateReq.Normalize returned %s %q, expected TXT %q", req.Type, req.Data, expected)
+	}
+
+	// existing string boundaries are kept
+	req = &DomainRecordCreateReq{Type: "TXT", Data: `"abc"   "def"`}
+	req.Normalize()
+	if req.Data != `"abc" "def"` {
+		t.Errorf("DomainRecordCreateReq.Normalize returned %q, expected %q", req.Data, `"abc" "def"`)
+	}
+
+	if err := (&DomainRecordCreateReq{Type: "TXT", Data: expected}).Validate(); err != nil {
+		t.Errorf("DomainRecordCreateReq.Validate returned %v for split TXT data", err)
+	}
+}
+
+func TestDomainRecordCreateReq_NormalizeInvalid(t *testing.T) {
+	req := &DomainRecordCreateReq{Type: "a", Data: " not-an-ip "}
+	req.Normalize()
+
+	if req.Type != "A" || req.Data != "not-an-ip" {
+		t.Errorf("DomainRecordCreateReq.Normalize returned %+v", req)
+	}
+	assertValidationFields(t, req.Validate(), []string{"data"})
+}
+
+func TestDomainRecordCreateReq_NormalizeEscapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      DomainRecordCreateReq
+		expected string
+	}{
+		{name: "escaped semicolon", req: DomainRecordCreateReq{Type: "TXT", Data: `"v=DKIM1\; k=rsa"`}, expected: `"v=DKIM1; k=rsa"`},
+		{name: "decimal escape", req: DomainRecordCreateReq{Type: "TXT", Data: `"\065\032b"`}, expected: `"A b"`},
+		{name: "non-ascii and control bytes", req: *NewTXTRecord("", "caf\u00e9\tx", 0), expected: `"caf\195\169\009x"`},
+		{name: "quote and backslash", req: *NewTXTRecord("", `say "hi" \o/`, 0), expected: `"say \"hi\" \\o/"`},
+		{
+			name:     "CAA",
+			req:      DomainRecordCreateReq{Type: "CAA", Data: `0 iodef "mailto:ca\064example.com"`},
+			expected: `0 iodef "mailto:ca@example.com"`,
+		},
+		{name: "CAA constructor", req: *NewCAARecord("", 0, "issue", "ca\u00e9", 0), expected: `0 issue "ca\195\169"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.Normalize()
+			if req.Data != tt.expected {
+				t.Errorf("DomainRecordCreateReq.Normalize returned %q, expected %q", req.Data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnquoteCharString(t *testing.T) {
+	for _, s := range []string{"", "plain", `"quoted" \ back`, "caf\u00e9\x00\xff"} {
+		got, err := unquoteCharString(quoteCharString(s))
+		if err != nil || got != s {
+			t.Errorf("unquoteCharString(quoteCharString(%q)) returned %q, %v", s, got, err)
+		}
+	}
+
+	for _, s := range []string{`"abc`, `"abc\"`, `"a"b"`, `"\256"`, `"\12"`, `"\1a2"`} {
+		if _, err := unquoteCharString(s); err == nil {
+			t.Errorf("unquoteCharString(%s) returned no error", s)
+		}
+	}
+}
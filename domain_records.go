@@ -16,6 +16,8 @@ type DomainRecordService interface {
 	Update(ctx context.Context, domain, recordID string, domainRecordUpdateReq *DomainRecordUpdateReq) error
 	Delete(ctx context.Context, domain, recordID string) error
 	List(ctx context.Context, domain string, options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error)
+
+	SyncRecords(ctx context.Context, domain string, desired []DomainRecord, opts *RecordSyncOptions) (*RecordSyncPlan, error)
 }
 
 // DomainRecordsServiceHandler handles interaction with the DNS Records methods for the Vultr API
@@ -51,6 +53,47 @@ type DomainRecordUpdateReq struct {
 	Priority *int    `json:"priority,omitempty"`
 }
 
+// DNS record types supported by Vultr DNS
+const (
+	RecordTypeA     = "A"
+	RecordTypeAAAA  = "AAAA"
+	RecordTypeCNAME = "CNAME"
+	RecordTypeNS    = "NS"
+	RecordTypeMX    = "MX"
+	RecordTypeSRV   = "SRV"
+	RecordTypeTXT   = "TXT"
+	RecordTypeCAA   = "CAA"
+	RecordTypeSSHFP = "SSHFP"
+)
+
+func validRecordType(recordType string) bool {
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA, RecordTypeCNAME, RecordTypeNS, RecordTypeMX,
+		RecordTypeSRV, RecordTypeTXT, RecordTypeCAA, RecordTypeSSHFP:
+		return true
+	}
+	return false
+}
+
+// Validate checks the request for mistakes the API would otherwise reject,
+// including record data that does not match its type
+func (d *DomainRecordCreateReq) Validate() error {
+	v := newValidation("DomainRecordCreateReq")
+
+	if !validRecordType(d.Type) {
+		v.addf("type", "unknown record type %q", d.Type)
+	}
+	v.required("data", d.Data)
+
+	if d.TTL < 0 {
+		v.addf("ttl", "cannot be negative")
+	}
+
+	validateRecordData(v, d.Type, d.Name, d.Data, d.Priority)
+
+	return v.err()
+}
+
 type domainRecordsBase struct {
 	Records []DomainRecord `json:"records,omitempty"`
 	Meta    *Meta          `json:"meta,omitempty"`
@@ -62,6 +105,10 @@ type domainRecordBase struct {
 
 // Create will add a DNS record.
 func (d *DomainRecordsServiceHandler) Create(ctx context.Context, domain string, domainRecordCreateReq *DomainRecordCreateReq) (*DomainRecord, *http.Response, error) { //nolint:lll
+	if err := d.client.validate(domainRecordCreateReq); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := d.client.NewRequest(ctx, http.MethodPost, fmt.Sprintf("%s/%s/records", domainPath, domain), domainRecordCreateReq)
 	if err != nil {
 		return nil, nil, err
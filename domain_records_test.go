@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -149,3 +150,38 @@ func TestDomainRecordsServiceHandler_List(t *testing.T) {
 		t.Errorf("DomainRecord.List meta returned %+v, expected %+v", meta, expectedMeta)
 	}
 }
+
+func TestDomainRecordCreateReq_Validate(t *testing.T) {
+	priority := 10
+	tests := []struct {
+		name   string
+		req    DomainRecordCreateReq
+		fields []string
+	}{
+		{name: "valid", req: DomainRecordCreateReq{Name: "www", Type: "A", Data: "127.0.0.1"}},
+		{name: "valid mx", req: DomainRecordCreateReq{Type: "MX", Data: "mail.vultr.com", Priority: &priority}},
+		{name: "bad type", req: DomainRecordCreateReq{Type: "PTR", Data: "vultr.com"}, fields: []string{"type"}},
+		{name: "missing data", req: DomainRecordCreateReq{Type: "TXT", TTL: -1}, fields: []string{"data", "ttl"}},
+		{name: "mx without priority", req: DomainRecordCreateReq{Type: "MX", Data: "mail.vultr.com"}, fields: []string{"priority"}},
+		{name: "ipv6 in a record", req: DomainRecordCreateReq{Type: "A", Data: "2001:db8::1"}, fields: []string{"data"}},
+		{name: "ipv4 in aaaa record", req: DomainRecordCreateReq{Type: "AAAA", Data: "192.0.2.1"}, fields: []string{"data"}},
+		{name: "cname at apex", req: DomainRecordCreateReq{Type: "CNAME", Data: "vultr.com."}, fields: []string{"name"}},
+		{name: "bad ns host", req: DomainRecordCreateReq{Name: "sub", Type: "NS", Data: "ns1 .vultr.com"}, fields: []string{"data"}},
+		{name: "priority range", req: DomainRecordCreateReq{Type: "MX", Data: "mail.vultr.com", Priority: IntToIntPtr(70000)}, fields: []string{"priority"}},
+		{name: "valid srv", req: DomainRecordCreateReq{Name: "_sip._tcp", Type: "SRV", Data: "5 5060 sip.vultr.com", Priority: &priority}},
+		{name: "srv name and data", req: DomainRecordCreateReq{Name: "sip", Type: "SRV", Data: "5 sip.vultr.com", Priority: &priority}, fields: []string{"name", "data"}},
+		{name: "srv port", req: DomainRecordCreateReq{Name: "_sip._tcp", Type: "SRV", Data: "5 99999 sip.vultr.com", Priority: &priority}, fields: []string{"data"}},
+		{name: "valid caa", req: DomainRecordCreateReq{Type: "CAA", Data: `0 issue "letsencrypt.org"`}},
+		{name: "caa flags", req: DomainRecordCreateReq{Type: "CAA", Data: `256 issue "letsencrypt.org"`}, fields: []string{"data"}},
+		{name: "caa tag", req: DomainRecordCreateReq{Type: "CAA", Data: `0 is-sue "letsencrypt.org"`}, fields: []string{"data"}},
+		{name: "long txt string", req: DomainRecordCreateReq{Type: "TXT", Data: `"` + strings.Repeat("a", 256) + `"`}, fields: []string{"data"}},
+		{name: "unterminated txt", req: DomainRecordCreateReq{Type: "TXT", Data: `"v=spf1`}, fields: []string{"data"}},
+		{name: "valid sshfp", req: DomainRecordCreateReq{Name: "host", Type: "SSHFP", Data: "4 2 123456789abcdef67890123456789abcdef67890123456789abcdef123456789"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.req.Validate(), tt.fields)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package govultr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	soaSerialDateLayout = "20060102"
+	soaSerialDaySlots   = 100
+)
+
+// SOARecord is the full SOA record of a zone. Vultr only exposes the primary
+// nameserver and contact email through the API and manages the serial and
+// timers itself, so the remaining fields are used for zone files.
+type SOARecord struct {
+	// MName is the primary nameserver as a fully qualified name
+	MName string
+	// RName is the contact mailbox in DNS form, such as hostmaster.example.com.
+	RName   string
+	Serial  uint32
+	Refresh int
+	Retry   int
+	Expire  int
+	Minimum int
+}
+
+// NextSoaSerial returns the serial following current using the YYYYMMDDnn
+// convention. Serials that are already ahead of today are incremented.
+func NextSoaSerial(current uint32, now time.Time) uint32 {
+	date, _ := strconv.ParseUint(now.UTC().Format(soaSerialDateLayout), 10, 32)
+	base := uint32(date) * soaSerialDaySlots
+	if current < base {
+		return base
+	}
+	return current + 1
+}
+
+// BumpSerial advances the serial so secondaries pick up a changed zone
+func (r *SOARecord) BumpSerial(now time.Time) {
+	r.Serial = NextSoaSerial(r.Serial, now)
+}
+
+// Soa returns the API representation of the record
+func (r *SOARecord) Soa() *Soa {
+	return &Soa{
+		NSPrimary: strings.TrimSuffix(strings.ToLower(r.MName), "."),
+		Email:     rnameToEmail(strings.TrimSuffix(r.RName, ".")),
+	}
+}
+
+// String returns the record in zone file format for the zone apex
+func (r *SOARecord) String() string {
+	return fmt.Sprintf("@\tIN\tSOA\t%s %s ( %d %d %d %d %d )",
+		fqdn(r.MName), fqdn(r.RName), r.Serial, r.Refresh, r.Retry, r.Expire, r.Minimum)
+}
+
+// Record returns the full SOA record with Vultr's default timers
+func (s *Soa) Record() *SOARecord {
+	return &SOARecord{
+		MName:   fqdn(strings.TrimSuffix(s.NSPrimary, ".")),
+		RName:   s.RName(),
+		Serial:  zoneSoaSerial,
+		Refresh: zoneSoaRefresh,
+		Retry:   zoneSoaRetry,
+		Expire:  zoneSoaExpire,
+		Minimum: zoneSoaMinimum,
+	}
+}
+
+// RName returns the contact email in DNS mailbox form with a trailing dot
+func (s *Soa) RName() string {
+	return fqdn(emailToRname(s.Email))
+}
+
+// Normalize lower cases the primary nameserver, drops its trailing dot and
+// converts a contact given in DNS mailbox form into an email address
+func (s *Soa) Normalize() {
+	s.NSPrimary = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s.NSPrimary)), ".")
+
+	email := strings.TrimSpace(s.Email)
+	if email != "" && !strings.Contains(email, "@") {
+		email = rnameToEmail(strings.TrimSuffix(email, "."))
+	}
+	s.Email = email
+}
+
+// Validate checks the request for mistakes the API would otherwise reject
+func (s *Soa) Validate() error {
+	v := newValidation("Soa")
+
+	if s.NSPrimary != "" {
+		if err := validateHostname(s.NSPrimary); err != nil {
+			v.addf("nsprimary", "%s", err)
+		} else if !strings.Contains(strings.TrimSuffix(s.NSPrimary, "."), ".") {
+			v.addf("nsprimary", "must be a fully qualified domain name")
+		}
+	}
+
+	if s.Email != "" {
+		at := strings.LastIndex(s.Email, "@")
+		switch {
+		case at <= 0 || strings.ContainsAny(s.Email[:at], " \t@"):
+			v.addf("email", "%q is not a valid email address", s.Email)
+		case validateHostname(s.Email[at+1:]) != nil:
+			v.addf("email", "%q does not have a valid domain", s.Email)
+		}
+	}
+
+	return v.err()
+}
+
+// DomainSoaInfo is the SOA and apex nameservers of a single domain
+type DomainSoaInfo struct {
+	Domain      string
+	Soa         *Soa
+	Nameservers []string
+}
+
+// SoaIssue describes an inconsistent or invalid setting on a domain
+type SoaIssue struct {
+	Domain  string
+	Field   string
+	Message string
+}
+
+func (s *SoaIssue) String() string {
+	return fmt.Sprintf("%s: %s %s", s.Domain, s.Field, s.Message)
+}
+
+// SoaConsistencyReport is the result of comparing SOA and NS settings across domains
+type SoaConsistencyReport struct {
+	Domains []DomainSoaInfo
+	Issues  []SoaIssue
+}
+
+// Consistent reports whether no issues were found
+func (r *SoaConsistencyReport) Consistent() bool {
+	return len(r.Issues) == 0
+}
+
+// CheckSoaConsistency compares the SOA and apex NS records of every domain on
+// the account and flags invalid settings and domains that differ from the rest
+func (d *DomainServiceHandler) CheckSoaConsistency(ctx context.Context) (*SoaConsistencyReport, error) {
+	domains, err := listAll(func(options *ListOptions) ([]Domain, *Meta, *http.Response, error) {
+		return d.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SoaConsistencyReport{}
+	for idx := range domains {
+		info, err := d.domainSoaInfo(ctx, domains[idx].Domain)
+		if err != nil {
+			return nil, err
+		}
+		report.Domains = append(report.Domains, *info)
+		report.Issues = append(report.Issues, checkDomainSoa(info)...)
+	}
+
+	report.Issues = append(report.Issues, checkSoaMajority(report.Domains)...)
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Domain != report.Issues[j].Domain {
+			return report.Issues[i].Domain < report.Issues[j].Domain
+		}
+		return report.Issues[i].Field < report.Issues[j].Field
+	})
+
+	return report, nil
+}
+
+func (d *DomainServiceHandler) domainSoaInfo(ctx context.Context, domain string) (*DomainSoaInfo, error) {
+	soa, _, err := d.GetSoa(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("getting soa for %s: %w", domain, err)
+	}
+	if soa == nil {
+		soa = &Soa{}
+	}
+
+	records, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return d.client.DomainRecord.List(ctx, domain, options)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing records for %s: %w", domain, err)
+	}
+
+	info := &DomainSoaInfo{Domain: domain, Soa: soa}
+	for idx := range records {
+		if records[idx].Type == RecordTypeNS && records[idx].Name == "" {
+			info.Nameservers = append(info.Nameservers, normalizeHostname(records[idx].Data))
+		}
+	}
+	sort.Strings(info.Nameservers)
+
+	return info, nil
+}
+
+func checkDomainSoa(info *DomainSoaInfo) []SoaIssue {
+	var issues []SoaIssue
+
+	if verr, ok := info.Soa.Validate().(*ValidationError); ok {
+		for _, f := range verr.Fields {
+			issues = append(issues, SoaIssue{Domain: info.Domain, Field: f.Field, Message: f.Message})
+		}
+	}
+
+	if len(info.Nameservers) == 0 {
+		issues = append(issues, SoaIssue{Domain: info.Domain, Field: "nameservers", Message: "has no NS records at the apex"})
+	} else if primary := normalizeHostname(info.Soa.NSPrimary); primary != "" && !containsString(info.Nameservers, primary) {
+		issues = append(issues, SoaIssue{
+			Domain:  info.Domain,
+			Field:   "nsprimary",
+			Message: fmt.Sprintf("%s is not one of the apex NS records", primary),
+		})
+	}
+
+	return issues
+}
+
+// checkSoaMajority flags domains whose settings differ from the most common value
+func checkSoaMajority(domains []DomainSoaInfo) []SoaIssue {
+	if len(domains) < 2 {
+		return nil
+	}
+
+	fields := map[string]func(info *DomainSoaInfo) string{
+		"nsprimary":   func(info *DomainSoaInfo) string { return normalizeHostname(info.Soa.NSPrimary) },
+		"email":       func(info *DomainSoaInfo) string { return strings.ToLower(info.Soa.Email) },
+		"nameservers": func(info *DomainSoaInfo) string { return strings.Join(info.Nameservers, ",") },
+	}
+
+	var issues []SoaIssue
+	for field, value := range fields {
+		counts := map[string]int{}
+		for idx := range domains {
+			counts[value(&domains[idx])]++
+		}
+
+		common := ""
+		for v, n := range counts {
+			if n > counts[common] || n == counts[common] && v < common {
+				common = v
+			}
+		}
+
+		for idx := range domains {
+			if v := value(&domains[idx]); v != common {
+				issues = append(issues, SoaIssue{
+					Domain:  domains[idx].Domain,
+					Field:   field,
+					Message: fmt.Sprintf("is %q while most domains use %q", v, common),
+				})
+			}
+		}
+	}
+
+	return issues
+}
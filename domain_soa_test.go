@@ -0,0 +1,143 @@
+package govultr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextSoaSerial(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := map[uint32]uint32{
+		0:          2024031500,
+		2023120104: 2024031500,
+		2024031500: 2024031501,
+		2024031599: 2024031600,
+		2030010100: 2030010101,
+	}
+
+	for current, expected := range tests {
+		if got := NextSoaSerial(current, now); got != expected {
+			t.Errorf("NextSoaSerial(%d) returned %d, expected %d", current, got, expected)
+		}
+	}
+}
+
+func TestSoa_Record(t *testing.T) {
+	soa := &Soa{NSPrimary: "ns1.vultr.com", Email: "host.master@example.com"}
+
+	record := soa.Record()
+	record.BumpSerial(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+
+	expected := "@\tIN\tSOA\tns1.vultr.com. host\\.master.example.com. ( 2024031500 10800 3600 604800 3600 )"
+	if record.String() != expected {
+		t.Errorf("SOARecord.String returned %q, expected %q", record.String(), expected)
+	}
+
+	if back := record.Soa(); !reflect.DeepEqual(back, soa) {
+		t.Errorf("SOARecord.Soa returned %+v, expected %+v", back, soa)
+	}
+}
+
+func TestSoa_Normalize(t *testing.T) {
+	soa := &Soa{NSPrimary: "NS1.Vultr.com.", Email: `host\.master.example.com.`}
+	soa.Normalize()
+
+	expected := &Soa{NSPrimary: "ns1.vultr.com", Email: "host.master@example.com"}
+	if !reflect.DeepEqual(soa, expected) {
+		t.Errorf("Soa.Normalize returned %+v, expected %+v", soa, expected)
+	}
+}
+
+func TestSoa_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		soa    Soa
+		fields []string
+	}{
+		{name: "valid", soa: Soa{NSPrimary: "ns1.vultr.com", Email: "admin@example.com"}},
+		{name: "partial update", soa: Soa{Email: "admin@example.com"}},
+		{name: "not fqdn", soa: Soa{NSPrimary: "ns1"}, fields: []string{"nsprimary"}},
+		{name: "bad host", soa: Soa{NSPrimary: "ns1..vultr.com"}, fields: []string{"nsprimary"}},
+		{name: "missing mailbox", soa: Soa{Email: "@example.com"}, fields: []string{"email"}},
+		{name: "bad email domain", soa: Soa{Email: "admin@exa mple.com"}, fields: []string{"email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.soa.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestZone_BumpSerial(t *testing.T) {
+	zone, err := ParseZone(strings.NewReader("@ IN SOA ns1.vultr.com. admin.example.com. ( 2024031503 1 2 3 4 )\n"), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone returned %+v", err)
+	}
+
+	if zone.Serial != 2024031503 {
+		t.Errorf("ParseZone serial returned %d, expected 2024031503", zone.Serial)
+	}
+
+	zone.BumpSerial(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	if zone.Serial != 2024031504 {
+		t.Errorf("Zone.BumpSerial returned %d, expected 2024031504", zone.Serial)
+	}
+}
+
+func TestDNSDomainServiceHandler_CheckSoaConsistency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domains":[{"domain":"a.com"},{"domain":"b.com"},{"domain":"c.com"}],"meta":{"total":3,"links":{}}}`)
+	})
+
+	soas := map[string]string{
+		"a.com": `{"nsprimary":"ns1.vultr.com","email":"admin@example.com"}`,
+		"b.com": `{"nsprimary":"ns1.vultr.com","email":"admin@example.com"}`,
+		"c.com": `{"nsprimary":"ns1.other.net","email":"dns@example.com"}`,
+	}
+	records := map[string]string{
+		"a.com": `{"type":"NS","name":"","data":"ns1.vultr.com"},{"type":"NS","name":"","data":"ns2.vultr.com"}`,
+		"b.com": `{"type":"NS","name":"","data":"ns2.vultr.com."},{"type":"NS","name":"","data":"NS1.vultr.com"}`,
+		"c.com": `{"type":"NS","name":"","data":"ns1.vultr.com"},{"type":"NS","name":"sub","data":"ns1.other.net"}`,
+	}
+
+	for domain := range soas {
+		mux.HandleFunc("/v2/domains/"+domain+"/soa", func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprintf(writer, `{"dns_soa":%s}`, soas[domain])
+		})
+		mux.HandleFunc("/v2/domains/"+domain+"/records", func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprintf(writer, `{"records":[%s],"meta":{"total":2,"links":{}}}`, records[domain])
+		})
+	}
+
+	report, err := client.Domain.CheckSoaConsistency(ctx)
+	if err != nil {
+		t.Fatalf("Domain.CheckSoaConsistency returned %+v", err)
+	}
+
+	if len(report.Domains) != 3 || !reflect.DeepEqual(report.Domains[1].Nameservers, []string{"ns1.vultr.com", "ns2.vultr.com"}) {
+		t.Errorf("Domain.CheckSoaConsistency returned domains %+v", report.Domains)
+	}
+
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, issue.Domain+" "+issue.Field)
+	}
+
+	expected := []string{"c.com email", "c.com nameservers", "c.com nsprimary", "c.com nsprimary"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Domain.CheckSoaConsistency returned issues %v, expected %v", got, expected)
+	}
+
+	if report.Consistent() {
+		t.Error("SoaConsistencyReport.Consistent returned true")
+	}
+}
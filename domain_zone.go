@@ -0,0 +1,597 @@
+package govultr
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Timers written to exported SOA records. Vultr manages these values itself
+// so they are informational only and ignored on import.
+const (
+	zoneSoaSerial  = 1
+	zoneSoaRefresh = 10800
+	zoneSoaRetry   = 3600
+	zoneSoaExpire  = 604800
+	zoneSoaMinimum = 3600
+)
+
+const (
+	mxFields  = 2
+	srvFields = 4
+	soaFields = 7
+)
+
+var ttlUnits = map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+// Zone is a DNS zone in the form used by Vultr DNS. Record names are relative
+// to Domain with the apex represented by an empty name, hostnames in Data are
+// fully qualified without a trailing dot and MX/SRV priorities are held in the
+// Priority field.
+type Zone struct {
+	Domain string
+	TTL    int
+	Soa    *Soa
+	// Serial is written to the SOA record of exported zone files
+	Serial  uint32
+	Records []DomainRecord
+}
+
+// ExportZone gathers the domain, its SOA and all of its records into a Zone
+func (d *DomainServiceHandler) ExportZone(ctx context.Context, domain string) (*Zone, error) {
+	dom, _, err := d.Get(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	soa, _, err := d.GetSoa(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return d.client.DomainRecord.List(ctx, domain, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Zone{Domain: dom.Domain, Soa: soa, Records: records}, nil
+}
+
+// ImportZone creates the zone's domain and all of its records. Records that already exist
+// on the new domain, such as the default NS records Vultr adds, are skipped.
+func (d *DomainServiceHandler) ImportZone(ctx context.Context, zone *Zone) (*Domain, error) {
+	domain, _, err := d.Create(ctx, &DomainReq{Domain: zone.Domain})
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := listAll(func(options *ListOptions) ([]DomainRecord, *Meta, *http.Response, error) {
+		return d.client.DomainRecord.List(ctx, zone.Domain, options)
+	})
+	if err != nil {
+		return domain, err
+	}
+
+	present := make(map[string]bool, len(existing))
+	for idx := range existing {
+		present[zoneRecordKey(&existing[idx])] = true
+	}
+
+	if zone.Soa != nil && zone.Soa.NSPrimary != "" {
+		if err := d.UpdateSoa(ctx, zone.Domain, zone.Soa); err != nil {
+			return domain, err
+		}
+	}
+
+	for idx := range zone.Records {
+		record := &zone.Records[idx]
+		if present[zoneRecordKey(record)] {
+			continue
+		}
+
+		if _, _, err := d.client.DomainRecord.Create(ctx, zone.Domain, recordCreateReq(record)); err != nil {
+			return domain, fmt.Errorf("creating %s record %q: %w", record.Type, record.Name, err)
+		}
+	}
+
+	return domain, nil
+}
+
+func zoneRecordKey(r *DomainRecord) string {
+	return strings.Join([]string{strings.ToLower(r.Name), r.Type, r.Data, strconv.Itoa(r.Priority)}, "\x00")
+}
+
+// ParseZone reads an RFC 1035 master file for domain. $ORIGIN and $TTL directives,
+// relative owner names, multi-line parenthesized records and quoted TXT strings are
+// supported. Records outside of domain and record types Vultr does not serve are
+// rejected.
+func ParseZone(r io.Reader, domain string) (*Zone, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	p := &zoneParser{
+		zone:   &Zone{Domain: domain},
+		origin: domain + ".",
+	}
+
+	entries, err := zoneEntries(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if err := p.parseEntry(entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+	}
+
+	return p.zone, nil
+}
+
+type zoneEntry struct {
+	line        int
+	fields      []string
+	inheritName bool
+}
+
+// zoneEntries splits the master file into logical entries, joining
+// parenthesized continuations and dropping comments
+func zoneEntries(r io.Reader) ([]zoneEntry, error) {
+	var (
+		entries []zoneEntry
+		current *zoneEntry
+		depth   int
+	)
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		fields, delta, err := tokenizeZoneLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if current == nil {
+			if len(fields) == 0 && delta == 0 {
+				continue
+			}
+			current = &zoneEntry{line: lineNo, inheritName: line != "" && unicode.IsSpace(rune(line[0]))}
+		}
+
+		current.fields = append(current.fields, fields...)
+		depth += delta
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNo)
+		}
+
+		if depth == 0 {
+			if len(current.fields) > 0 {
+				entries = append(entries, *current)
+			}
+			current = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated parentheses")
+	}
+
+	return entries, nil
+}
+
+// tokenizeZoneLine splits a single line into fields, keeping quoted strings
+// intact including their quotes, and reports the change in parenthesis depth
+func tokenizeZoneLine(line string) (fields []string, depth int, err error) {
+	var b strings.Builder
+	inQuote, escaped := false, false
+
+	flush := func() {
+		if b.Len() > 0 {
+			fields = append(fields, b.String())
+			b.Reset()
+		}
+	}
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			b.WriteRune(c)
+			escaped = true
+		case c == '"':
+			b.WriteRune(c)
+			inQuote = !inQuote
+		case inQuote:
+			b.WriteRune(c)
+		case c == ';':
+			flush()
+			return fields, depth, nil
+		case c == '(' || c == ')':
+			flush()
+			if c == '(' {
+				depth++
+			} else {
+				depth--
+			}
+		case unicode.IsSpace(c):
+			flush()
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	if inQuote {
+		return nil, 0, fmt.Errorf("unterminated quoted string")
+	}
+
+	flush()
+	return fields, depth, nil
+}
+
+type zoneParser struct {
+	zone       *Zone
+	origin     string
+	defaultTTL int
+	lastName   string
+	lastTTL    int
+}
+
+func (p *zoneParser) parseEntry(entry zoneEntry) error {
+	fields := entry.fields
+
+	switch strings.ToUpper(fields[0]) {
+	case "$ORIGIN":
+		if len(fields) != 2 {
+			return fmt.Errorf("$ORIGIN requires a single domain name")
+		}
+		p.origin = p.absolute(fields[1])
+		return nil
+	case "$TTL":
+		if len(fields) != 2 {
+			return fmt.Errorf("$TTL requires a single value")
+		}
+		ttl, err := parseZoneTTL(fields[1])
+		if err != nil {
+			return err
+		}
+		p.defaultTTL = ttl
+		p.zone.TTL = ttl
+		return nil
+	case "$INCLUDE", "$GENERATE":
+		return fmt.Errorf("%s is not supported", fields[0])
+	}
+
+	name := p.lastName
+	if !entry.inheritName {
+		name = p.absolute(fields[0])
+		fields = fields[1:]
+	}
+	if name == "" {
+		return fmt.Errorf("record has no owner name")
+	}
+	p.lastName = name
+
+	ttl, recordType, rdata, err := p.splitRecord(fields)
+	if err != nil {
+		return err
+	}
+
+	return p.addRecord(name, ttl, recordType, rdata)
+}
+
+// splitRecord separates the optional TTL and class from the type and rdata
+func (p *zoneParser) splitRecord(fields []string) (ttl int, recordType string, rdata []string, err error) {
+	ttl = -1
+	for len(fields) > 0 {
+		field := fields[0]
+		if strings.EqualFold(field, "IN") {
+			fields = fields[1:]
+			continue
+		}
+		if ttl < 0 && field[0] >= '0' && field[0] <= '9' {
+			if ttl, err = parseZoneTTL(field); err != nil {
+				return 0, "", nil, err
+			}
+			fields = fields[1:]
+			continue
+		}
+		break
+	}
+
+	if len(fields) < 2 {
+		return 0, "", nil, fmt.Errorf("record is missing its type or data")
+	}
+
+	switch {
+	case ttl >= 0:
+	case p.defaultTTL > 0:
+		ttl = p.defaultTTL
+	default:
+		ttl = p.lastTTL
+	}
+	p.lastTTL = ttl
+
+	return ttl, strings.ToUpper(fields[0]), fields[1:], nil
+}
+
+func (p *zoneParser) addRecord(name string, ttl int, recordType string, rdata []string) error {
+	relative, ok := zoneRelativeName(name, p.zone.Domain)
+	if !ok {
+		return fmt.Errorf("%s is outside of zone %s", strings.TrimSuffix(name, "."), p.zone.Domain)
+	}
+
+	record := DomainRecord{Name: relative, Type: recordType, TTL: ttl}
+
+	switch recordType {
+	case "SOA":
+		return p.parseSoa(rdata)
+	case RecordTypeA, RecordTypeAAAA:
+		ip := net.ParseIP(rdata[0])
+		if len(rdata) != 1 || ip == nil || (ip.To4() != nil) != (recordType == RecordTypeA) {
+			return fmt.Errorf("invalid %s record data %q", recordType, strings.Join(rdata, " "))
+		}
+		record.Data = rdata[0]
+	case RecordTypeCNAME, RecordTypeNS:
+		if len(rdata) != 1 {
+			return fmt.Errorf("%s record requires a single hostname", recordType)
+		}
+		record.Data = p.hostname(rdata[0])
+	case RecordTypeMX:
+		if len(rdata) != mxFields {
+			return fmt.Errorf("MX record requires a preference and hostname")
+		}
+		priority, err := strconv.Atoi(rdata[0])
+		if err != nil {
+			return fmt.Errorf("invalid MX preference %q", rdata[0])
+		}
+		record.Priority, record.Data = priority, p.hostname(rdata[1])
+	case RecordTypeSRV:
+		if len(rdata) != srvFields {
+			return fmt.Errorf("SRV record requires priority, weight, port and target")
+		}
+		priority, err := strconv.Atoi(rdata[0])
+		if err != nil {
+			return fmt.Errorf("invalid SRV priority %q", rdata[0])
+		}
+		record.Priority = priority
+		record.Data = strings.Join([]string{rdata[1], rdata[2], p.hostname(rdata[3])}, " ")
+	case RecordTypeTXT:
+		strs, err := unescapeZoneTXT(rdata)
+		if err != nil {
+			return err
+		}
+		record.Data = quoteTXT(strs)
+	case RecordTypeCAA, RecordTypeSSHFP:
+		record.Data = strings.Join(rdata, " ")
+	default:
+		return fmt.Errorf("record type %s is not supported by Vultr DNS", recordType)
+	}
+
+	p.zone.Records = append(p.zone.Records, record)
+	return nil
+}
+
+func (p *zoneParser) parseSoa(rdata []string) error {
+	if len(rdata) != soaFields {
+		return fmt.Errorf("SOA record requires seven fields")
+	}
+
+	p.zone.Soa = &Soa{
+		NSPrimary: p.hostname(rdata[0]),
+		Email:     rnameToEmail(p.hostname(rdata[1])),
+	}
+
+	serial, err := strconv.ParseUint(rdata[2], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid SOA serial %q", rdata[2])
+	}
+	p.zone.Serial = uint32(serial)
+
+	return nil
+}
+
+// absolute returns name as a fully qualified name with a trailing dot
+func (p *zoneParser) absolute(name string) string {
+	if name == "@" {
+		return p.origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name + "." + p.origin)
+}
+
+// hostname returns name fully qualified without the trailing dot
+func (p *zoneParser) hostname(name string) string {
+	return strings.TrimSuffix(p.absolute(name), ".")
+}
+
+func parseZoneTTL(value string) (int, error) {
+	total, current := 0, 0
+	hasUnit := false
+
+	for _, c := range []byte(strings.ToLower(value)) {
+		switch {
+		case c >= '0' && c <= '9':
+			current = current*10 + int(c-'0')
+		case ttlUnits[c] > 0:
+			total += current * ttlUnits[c]
+			current, hasUnit = 0, true
+		default:
+			return 0, fmt.Errorf("invalid TTL %q", value)
+		}
+	}
+
+	if hasUnit && current != 0 {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+
+	return total + current, nil
+}
+
+// zoneRelativeName converts a fully qualified name into the name Vultr uses for
+// a record in domain, returning false when the name is outside of the domain
+func zoneRelativeName(name, domain string) (string, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	if name == domain {
+		return "", true
+	}
+
+	if strings.HasSuffix(name, "."+domain) {
+		return strings.TrimSuffix(name, "."+domain), true
+	}
+
+	return "", false
+}
+
+// unescapeZoneTXT decodes the quoted or unquoted character strings of TXT rdata
+func unescapeZoneTXT(rdata []string) ([]string, error) {
+	strs := make([]string, len(rdata))
+	for i, field := range rdata {
+		var err error
+		if strings.HasPrefix(field, `"`) {
+			strs[i], err = unquoteCharString(field)
+		} else {
+			strs[i], err = unescapeCharString(field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid TXT record data: %w", err)
+		}
+	}
+	return strs, nil
+}
+
+// quoteTXT quotes each string as an RFC 1035 character string
+func quoteTXT(strs []string) string {
+	quoted := make([]string, len(strs))
+	for i, s := range strs {
+		quoted[i] = quoteCharString(s)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// rnameToEmail converts an SOA RNAME such as hostmaster.example.com into an email
+// address. The first unescaped dot separates the mailbox from the domain.
+func rnameToEmail(rname string) string {
+	for i := 0; i < len(rname); i++ {
+		switch rname[i] {
+		case '\\':
+			i++
+		case '.':
+			return strings.ReplaceAll(rname[:i], `\.`, ".") + "@" + rname[i+1:]
+		}
+	}
+	return rname
+}
+
+// emailToRname converts an email address into an SOA RNAME, escaping dots in the mailbox
+func emailToRname(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	return strings.ReplaceAll(email[:at], ".", `\.`) + "." + email[at+1:]
+}
+
+// WriteTo writes the zone in RFC 1035 master file format
+func (z *Zone) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", z.Domain)
+	if z.TTL > 0 {
+		fmt.Fprintf(&b, "$TTL %d\n", z.TTL)
+	}
+
+	if z.Soa != nil && z.Soa.NSPrimary != "" {
+		soa := z.Soa.Record()
+		if z.Serial > 0 {
+			soa.Serial = z.Serial
+		}
+		b.WriteString(soa.String())
+		b.WriteByte('\n')
+	}
+
+	records := make([]DomainRecord, len(z.Records))
+	copy(records, z.Records)
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Type < records[j].Type
+	})
+
+	for idx := range records {
+		b.WriteString(zoneRecordLine(&records[idx]))
+		b.WriteByte('\n')
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// BumpSerial advances the zone's SOA serial using the YYYYMMDDnn convention
+func (z *Zone) BumpSerial(now time.Time) {
+	z.Serial = NextSoaSerial(z.Serial, now)
+}
+
+// String returns the zone in RFC 1035 master file format
+func (z *Zone) String() string {
+	var b strings.Builder
+	_, _ = z.WriteTo(&b)
+	return b.String()
+}
+
+func zoneRecordLine(r *DomainRecord) string {
+	name := r.Name
+	if name == "" {
+		name = "@"
+	}
+
+	data := r.Data
+	switch r.Type {
+	case RecordTypeCNAME, RecordTypeNS:
+		data = zoneAbsoluteHost(data)
+	case RecordTypeMX:
+		data = fmt.Sprintf("%d %s", r.Priority, zoneAbsoluteHost(data))
+	case RecordTypeSRV:
+		if parts := strings.Fields(data); len(parts) == srvFields-1 {
+			parts[2] = zoneAbsoluteHost(parts[2])
+			data = strings.Join(parts, " ")
+		}
+		data = fmt.Sprintf("%d %s", r.Priority, data)
+	case RecordTypeTXT:
+		if !strings.HasPrefix(data, `"`) {
+			data = quoteCharString(data)
+		}
+	}
+
+	ttl := ""
+	if r.TTL > 0 {
+		ttl = strconv.Itoa(r.TTL)
+	}
+
+	return fmt.Sprintf("%s\t%s\tIN\t%s\t%s", name, ttl, r.Type, data)
+}
+
+// zoneAbsoluteHost adds the trailing dot Vultr omits from hostnames
+func zoneAbsoluteHost(host string) string {
+	if host == "" || strings.HasSuffix(host, ".") {
+		return host
+	}
+	return host + "."
+}
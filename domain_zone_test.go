@@ -0,0 +1,223 @@
+package govultr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.vultr.com. host\.master.example.com. (
+		2024010101 ; serial
+		7200       ; refresh
+		3600 1209600 300 )
+@		IN	NS	ns1.vultr.com.
+		IN	NS	ns2.vultr.com.
+@	300	IN	A	192.0.2.1
+www		IN	CNAME	@
+mail	IN	300	AAAA	2001:db8::25
+@		MX	10 mail
+@		MX	20 backup.mail.example.net.
+_sip._tcp	SRV	5 60 5060 sip
+@		TXT	"v=spf1 include:_spf.example.com ~all" ; spf
+long	TXT	"part one" "part two; not a comment"
+@		CAA	0 issue "letsencrypt.org"
+$ORIGIN sub.example.com.
+api	1d	A	192.0.2.2
+`
+
+func TestParseZone(t *testing.T) {
+	zone, err := ParseZone(strings.NewReader(testZoneFile), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone returned %+v", err)
+	}
+
+	expectedSoa := &Soa{NSPrimary: "ns1.vultr.com", Email: "host.master@example.com"}
+	if !reflect.DeepEqual(zone.Soa, expectedSoa) {
+		t.Errorf("ParseZone soa returned %+v, expected %+v", zone.Soa, expectedSoa)
+	}
+
+	expected := []DomainRecord{
+		{Name: "", Type: "NS", Data: "ns1.vultr.com", TTL: 3600},
+		{Name: "", Type: "NS", Data: "ns2.vultr.com", TTL: 3600},
+		{Name: "", Type: "A", Data: "192.0.2.1", TTL: 300},
+		{Name: "www", Type: "CNAME", Data: "example.com", TTL: 3600},
+		{Name: "mail", Type: "AAAA", Data: "2001:db8::25", TTL: 300},
+		{Name: "", Type: "MX", Data: "mail.example.com", Priority: 10, TTL: 3600},
+		{Name: "", Type: "MX", Data: "backup.mail.example.net", Priority: 20, TTL: 3600},
+		{Name: "_sip._tcp", Type: "SRV", Data: "60 5060 sip.example.com", Priority: 5, TTL: 3600},
+		{Name: "", Type: "TXT", Data: `"v=spf1 include:_spf.example.com ~all"`, TTL: 3600},
+		{Name: "long", Type: "TXT", Data: `"part one" "part two; not a comment"`, TTL: 3600},
+		{Name: "", Type: "CAA", Data: `0 issue "letsencrypt.org"`, TTL: 3600},
+		{Name: "api.sub", Type: "A", Data: "192.0.2.2", TTL: 86400},
+	}
+
+	if !reflect.DeepEqual(zone.Records, expected) {
+		t.Errorf("ParseZone records returned\n%+v\nexpected\n%+v", zone.Records, expected)
+	}
+}
+
+func TestParseZone_Errors(t *testing.T) {
+	tests := map[string]string{
+		"outside zone":     "www.example.net. IN A 192.0.2.1",
+		"unsupported type": "@ IN PTR host",
+		"bad address":      "@ IN A 2001:db8::1",
+		"bad mx":           "@ IN MX mail",
+		"unbalanced":       "@ IN SOA ns1 host ( 1 2 3",
+		"unterminated":     `@ IN TXT "open`,
+		"include":          "$INCLUDE other.zone",
+		"bad ttl":          "$TTL 1x",
+	}
+
+	for name, zoneFile := range tests {
+		if _, err := ParseZone(strings.NewReader(zoneFile), "example.com"); err == nil {
+			t.Errorf("ParseZone %s expected an error", name)
+		}
+	}
+}
+
+func TestZone_RoundTrip(t *testing.T) {
+	zone, err := ParseZone(strings.NewReader(testZoneFile), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone returned %+v", err)
+	}
+
+	output := zone.String()
+	for _, line := range []string{
+		"$ORIGIN example.com.",
+		"@\tIN\tSOA\tns1.vultr.com. host\\.master.example.com. (",
+		"@\t3600\tIN\tMX\t10 mail.example.com.",
+		"_sip._tcp\t3600\tIN\tSRV\t5 60 5060 sip.example.com.",
+		"www\t3600\tIN\tCNAME\texample.com.",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Zone.String missing %q in\n%s", line, output)
+		}
+	}
+
+	reparsed, err := ParseZone(strings.NewReader(output), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone of exported zone returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(reparsed.Soa, zone.Soa) || len(reparsed.Records) != len(zone.Records) {
+		t.Errorf("exported zone did not round trip:\n%s", output)
+	}
+}
+
+func TestZone_RoundTripEscapes(t *testing.T) {
+	input := "$ORIGIN example.com.\n" +
+		`bare	TXT	semi\059colon\009tab` + "\n" +
+		`dkim	TXT	"v=DKIM1\; k=rsa" "caf\195\169 \"q\" \\"` + "\n"
+
+	zone, err := ParseZone(strings.NewReader(input), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone returned %+v", err)
+	}
+
+	expected := []string{`"semi;colon\009tab"`, `"v=DKIM1; k=rsa" "caf\195\169 \"q\" \\"`}
+	for idx, data := range expected {
+		if zone.Records[idx].Data != data {
+			t.Errorf("record %d data = %q, expected %q", idx, zone.Records[idx].Data, data)
+		}
+	}
+
+	// unquoted data from the API is escaped on export
+	zone.Records = append(zone.Records, DomainRecord{Name: "raw", Type: "TXT", Data: "na\u00efve\x01"})
+	output := zone.String()
+	if !strings.Contains(output, `"na\195\175ve\001"`) {
+		t.Errorf("Zone.String did not escape raw TXT data:\n%s", output)
+	}
+
+	reparsed, err := ParseZone(strings.NewReader(output), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone of exported zone returned %+v", err)
+	}
+	expected = append(expected, `"na\195\175ve\001"`)
+	for idx, data := range expected {
+		if reparsed.Records[idx].Data != data {
+			t.Errorf("reparsed record %d data = %q, expected %q", idx, reparsed.Records[idx].Data, data)
+		}
+	}
+}
+
+func TestDomainServiceHandler_ExportZone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/domains/example.com", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domain":{"domain":"example.com","date_created":"2019-11-06T16:07:58+00:00","dns_sec":"disabled"}}`)
+	})
+	mux.HandleFunc("/v2/domains/example.com/soa", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"dns_soa":{"nsprimary":"ns1.vultr.com","email":"admin@example.com"}}`)
+	})
+	mux.HandleFunc("/v2/domains/example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(writer, `{"records":[{"id":"1","type":"A","name":"","data":"192.0.2.1","ttl":300}],"meta":{"total":2,"links":{"next":"page2"}}}`)
+			return
+		}
+		fmt.Fprint(writer, `{"records":[{"id":"2","type":"MX","name":"","data":"mail.example.com","priority":10,"ttl":300}],"meta":{"total":2,"links":{}}}`)
+	})
+
+	zone, err := client.Domain.ExportZone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Domain.ExportZone returned %+v", err)
+	}
+
+	if zone.Domain != "example.com" || zone.Soa.Email != "admin@example.com" || len(zone.Records) != 2 {
+		t.Errorf("Domain.ExportZone returned %+v", zone)
+	}
+}
+
+func TestDomainServiceHandler_ImportZone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/domains", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"domain":{"domain":"example.com"}}`)
+	})
+	mux.HandleFunc("/v2/domains/example.com/soa", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	var created []DomainRecordCreateReq
+	mux.HandleFunc("/v2/domains/example.com/records", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, `{"records":[{"id":"ns1","type":"NS","name":"","data":"ns1.vultr.com","ttl":3600}],"meta":{"total":1,"links":{}}}`)
+			return
+		}
+
+		var req DomainRecordCreateReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, req)
+		fmt.Fprint(writer, `{"record":{}}`)
+	})
+
+	zone, err := ParseZone(strings.NewReader(testZoneFile), "example.com")
+	if err != nil {
+		t.Fatalf("ParseZone returned %+v", err)
+	}
+
+	if _, err := client.Domain.ImportZone(ctx, zone); err != nil {
+		t.Fatalf("Domain.ImportZone returned %+v", err)
+	}
+
+	if len(created) != len(zone.Records)-1 {
+		t.Errorf("Domain.ImportZone created %d records, expected %d", len(created), len(zone.Records)-1)
+	}
+
+	for _, req := range created {
+		if req.Type == "NS" && req.Data == "ns1.vultr.com" {
+			t.Errorf("Domain.ImportZone recreated an existing record")
+		}
+		if (req.Type == "MX" || req.Type == "SRV") && req.Priority == nil {
+			t.Errorf("Domain.ImportZone did not send a priority for %+v", req)
+		}
+	}
+}
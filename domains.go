@@ -2,14 +2,19 @@ package govultr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
 
 const domainPath = "/v2/domains"
 
+// ErrZoneNotFound is returned when no domain on the account hosts a name
+var ErrZoneNotFound = errors.New("no matching domain found on the account")
+
 // DomainService is the interface to interact with the DNS endpoints on the Vultr API
 // https://www.vultr.com/api/#tag/dns
 type DomainService interface {
@@ -21,8 +26,14 @@ type DomainService interface {
 
 	GetSoa(ctx context.Context, domain string) (*Soa, *http.Response, error)
 	UpdateSoa(ctx context.Context, domain string, soaReq *Soa) error
+	CheckSoaConsistency(ctx context.Context) (*SoaConsistencyReport, error)
+
+	GetDNSSec(ctx context.Context, domain string) (*DNSSec, *http.Response, error)
+	EnableDNSSec(ctx context.Context, domain string, wait *WaitOptions) (*DNSSec, error)
 
-	GetDNSSec(ctx context.Context, domain string) ([]string, *http.Response, error)
+	ExportZone(ctx context.Context, domain string) (*Zone, error)
+	ImportZone(ctx context.Context, zone *Zone) (*Domain, error)
+	FindZone(ctx context.Context, fqdn string) (string, error)
 }
 
 // DomainServiceHandler handles interaction with the DNS methods for the Vultr API
@@ -164,6 +175,10 @@ func (d *DomainServiceHandler) GetSoa(ctx context.Context, domain string) (*Soa,
 
 // UpdateSoa will update the SOA record information for a domain.
 func (d *DomainServiceHandler) UpdateSoa(ctx context.Context, domain string, soaReq *Soa) error {
+	if err := d.client.validate(soaReq); err != nil {
+		return err
+	}
+
 	req, err := d.client.NewRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/%s/soa", domainPath, domain), soaReq)
 	if err != nil {
 		return err
@@ -173,8 +188,9 @@ func (d *DomainServiceHandler) UpdateSoa(ctx context.Context, domain string, soa
 	return err
 }
 
-// GetDNSSec gets the DNSSec keys for a domain (if enabled)
-func (d *DomainServiceHandler) GetDNSSec(ctx context.Context, domain string) ([]string, *http.Response, error) {
+// GetDNSSec gets the parsed DNSSec keys and DS records for a domain (if enabled).
+// Records that cannot be parsed do not fail the call; they are listed in Skipped.
+func (d *DomainServiceHandler) GetDNSSec(ctx context.Context, domain string) (*DNSSec, *http.Response, error) {
 	req, err := d.client.NewRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/dnssec", domainPath, domain), nil)
 	if err != nil {
 		return nil, nil, err
@@ -186,5 +202,30 @@ func (d *DomainServiceHandler) GetDNSSec(ctx context.Context, domain string) ([]
 		return nil, resp, err
 	}
 
-	return dnsSec.DNSSec, resp, nil
+	// records that fail to parse are reported in parsed.Skipped
+	parsed, _ := ParseDNSSec(dnsSec.DNSSec)
+	return parsed, resp, nil
+}
+
+// FindZone returns the longest domain on the account that hosts fqdn
+func (d *DomainServiceHandler) FindZone(ctx context.Context, fqdn string) (string, error) {
+	domains, err := listAll(func(options *ListOptions) ([]Domain, *Meta, *http.Response, error) {
+		return d.List(ctx, options)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	best := ""
+	for idx := range domains {
+		domain := strings.ToLower(domains[idx].Domain)
+		if _, ok := zoneRelativeName(fqdn, domain); ok && len(domain) > len(best) {
+			best = domain
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("%s: %w", fqdn, ErrZoneNotFound)
+	}
+	return best, nil
 }
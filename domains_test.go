@@ -183,13 +183,61 @@ func TestDNSDomainServiceHandler_DNSSecInfo(t *testing.T) {
 		t.Errorf("Domain.GetDnsSec returned %+v, expected %+v", err, nil)
 	}
 
-	expected := []string{
-		"example.com IN DNSKEY 257 3 13 kRrxANp7YTGqVbaWtMy8hhsK0jcG4ajjICZKMb4fKv79Vx/RSn76vNjzIT7/Uo0BXil01Fk8RRQc4nWZctGJBA==",
-		"example.com IN DS 27933 13 1 2d9ac457e5c11a104e25d971d0a6254562bddde7",
-		"example.com IN DS 27933 13 2 8858e7b0dfb881280ce2ca1e0eafcd93d5b53687c21da284d4f8799ba82208a9",
+	expected := &DNSSec{
+		Records: []string{
+			"example.com IN DNSKEY 257 3 13 kRrxANp7YTGqVbaWtMy8hhsK0jcG4ajjICZKMb4fKv79Vx/RSn76vNjzIT7/Uo0BXil01Fk8RRQc4nWZctGJBA==",
+			"example.com IN DS 27933 13 1 2d9ac457e5c11a104e25d971d0a6254562bddde7",
+			"example.com IN DS 27933 13 2 8858e7b0dfb881280ce2ca1e0eafcd93d5b53687c21da284d4f8799ba82208a9",
+		},
+		DNSKeys: []DNSKey{
+			{
+				Owner:     "example.com",
+				Flags:     257,
+				Protocol:  3,
+				Algorithm: 13,
+				PublicKey: "kRrxANp7YTGqVbaWtMy8hhsK0jcG4ajjICZKMb4fKv79Vx/RSn76vNjzIT7/Uo0BXil01Fk8RRQc4nWZctGJBA==",
+			},
+		},
+		DS: []DSRecord{
+			{Owner: "example.com", KeyTag: 27933, Algorithm: 13, DigestType: 1, Digest: "2D9AC457E5C11A104E25D971D0A6254562BDDDE7"},
+			{
+				Owner:      "example.com",
+				KeyTag:     27933,
+				Algorithm:  13,
+				DigestType: 2,
+				Digest:     "8858E7B0DFB881280CE2CA1E0EAFCD93D5B53687C21DA284D4F8799BA82208A9",
+			},
+		},
 	}
 
 	if !reflect.DeepEqual(dnsSec, expected) {
 		t.Errorf("Domain.GetDnsSec returned %+v, expected %+v", dnsSec, expected)
 	}
 }
+
+func TestDNSDomainServiceHandler_DNSSecInfoSkipped(t *testing.T) {
+	setup()
+	defer teardown()
+	mux.HandleFunc("/v2/domains/vultr.com/dnssec", func(writer http.ResponseWriter, request *http.Request) {
+		response := `{"dns_sec":[
+		"example.com IN DS 27933 13 2 nothex",
+		"example.com IN DS 27933 13 1 2d9ac457e5c11a104e25d971d0a6254562bddde7"
+]}`
+		fmt.Fprint(writer, response)
+	})
+
+	dnsSec, _, err := client.Domain.GetDNSSec(ctx, "vultr.com")
+	if err != nil {
+		t.Fatalf("Domain.GetDnsSec returned %+v, expected %+v", err, nil)
+	}
+
+	if len(dnsSec.Records) != 2 {
+		t.Errorf("Domain.GetDnsSec returned %d records, expected 2", len(dnsSec.Records))
+	}
+	if len(dnsSec.DS) != 1 || dnsSec.DS[0].DigestType != 1 {
+		t.Errorf("Domain.GetDnsSec returned DS %+v, expected the SHA-1 record", dnsSec.DS)
+	}
+	if len(dnsSec.Skipped) != 1 || dnsSec.Skipped[0].Record != "example.com IN DS 27933 13 2 nothex" {
+		t.Errorf("Domain.GetDnsSec returned skipped %+v, expected the SHA-256 record", dnsSec.Skipped)
+	}
+}
@@ -0,0 +1,264 @@
+package govultr
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FirewallFindingKind identifies the problem a FirewallFinding reports
+type FirewallFindingKind string
+
+// Kinds of firewall findings
+const (
+	FirewallFindingDuplicate FirewallFindingKind = "duplicate"
+	FirewallFindingShadowed  FirewallFindingKind = "shadowed"
+	FirewallFindingExposed   FirewallFindingKind = "exposed"
+	FirewallFindingUnused    FirewallFindingKind = "unused"
+)
+
+// FirewallFindingSeverity ranks how urgently a finding should be addressed
+type FirewallFindingSeverity string
+
+// Severities of firewall findings
+const (
+	FirewallSeverityLow    FirewallFindingSeverity = "low"
+	FirewallSeverityMedium FirewallFindingSeverity = "medium"
+	FirewallSeverityHigh   FirewallFindingSeverity = "high"
+)
+
+// sensitiveFirewallPorts are services that should not be reachable from the whole internet
+var sensitiveFirewallPorts = map[int]string{
+	22:   "SSH",
+	3389: "RDP",
+	3306: "MySQL",
+	5432: "PostgreSQL",
+	6379: "Redis",
+}
+
+// FirewallFinding is a single problem found in a firewall group
+type FirewallFinding struct {
+	GroupID  string
+	Kind     FirewallFindingKind
+	Severity FirewallFindingSeverity
+	// RuleID is the rule the finding is about, zero for findings about the group
+	RuleID int
+	// RelatedRuleID is the rule that duplicates or shadows RuleID
+	RelatedRuleID int
+	Message       string
+}
+
+func (f *FirewallFinding) String() string {
+	if f.RuleID == 0 {
+		return fmt.Sprintf("[%s] group %s: %s", f.Severity, f.GroupID, f.Message)
+	}
+	return fmt.Sprintf("[%s] group %s rule %d: %s", f.Severity, f.GroupID, f.RuleID, f.Message)
+}
+
+// FirewallReport holds the findings for one or more firewall groups
+type FirewallReport struct {
+	Groups   []FirewallGroup
+	Findings []FirewallFinding
+}
+
+// BySeverity returns the findings with the given severity
+func (r *FirewallReport) BySeverity(severity FirewallFindingSeverity) []FirewallFinding {
+	var findings []FirewallFinding
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// Analyze reports duplicate, shadowed and overly permissive rules in a firewall
+// group and whether the group is attached to any instances
+func (f *FireWallGroupServiceHandler) Analyze(ctx context.Context, fwGroupID string) (*FirewallReport, error) {
+	group, _, err := f.Get(ctx, fwGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.analyzeGroups(ctx, []FirewallGroup{*group})
+}
+
+// AnalyzeAll runs Analyze over every firewall group on the account
+func (f *FireWallGroupServiceHandler) AnalyzeAll(ctx context.Context) (*FirewallReport, error) {
+	groups, err := listAll(func(options *ListOptions) ([]FirewallGroup, *Meta, *http.Response, error) {
+		return f.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f.analyzeGroups(ctx, groups)
+}
+
+func (f *FireWallGroupServiceHandler) analyzeGroups(ctx context.Context, groups []FirewallGroup) (*FirewallReport, error) {
+	report := &FirewallReport{Groups: groups}
+
+	for idx := range groups {
+		rules, err := listAll(func(options *ListOptions) ([]FirewallRule, *Meta, *http.Response, error) {
+			return f.client.FirewallRule.List(ctx, groups[idx].ID, options)
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = append(report.Findings, AnalyzeFirewallRules(&groups[idx], rules)...)
+	}
+
+	return report, nil
+}
+
+// AnalyzeFirewallRules inspects the rules of a group without calling the API
+func AnalyzeFirewallRules(group *FirewallGroup, rules []FirewallRule) []FirewallFinding {
+	var findings []FirewallFinding
+
+	if group.InstanceCount == 0 {
+		findings = append(findings, FirewallFinding{
+			GroupID:  group.ID,
+			Kind:     FirewallFindingUnused,
+			Severity: FirewallSeverityLow,
+			Message:  "group is not attached to any instances",
+		})
+	}
+
+	normalized := make([]FirewallRuleReq, len(rules))
+	for idx := range rules {
+		normalized[idx] = firewallRuleToReq(&rules[idx])
+	}
+
+	for idx := range rules {
+		if finding := exposedRuleFinding(group.ID, &rules[idx], &normalized[idx]); finding != nil {
+			findings = append(findings, *finding)
+		}
+
+		if finding := redundantRuleFinding(group.ID, rules, normalized, idx); finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].RuleID < findings[j].RuleID
+	})
+
+	return findings
+}
+
+// redundantRuleFinding reports rule idx if an earlier rule is identical or any other rule covers it
+func redundantRuleFinding(groupID string, rules []FirewallRule, normalized []FirewallRuleReq, idx int) *FirewallFinding {
+	key := firewallRuleKey(&normalized[idx])
+
+	for other := range rules {
+		if other == idx {
+			continue
+		}
+
+		if firewallRuleKey(&normalized[other]) == key {
+			if other > idx {
+				continue
+			}
+			return &FirewallFinding{
+				GroupID:       groupID,
+				Kind:          FirewallFindingDuplicate,
+				Severity:      FirewallSeverityLow,
+				RuleID:        rules[idx].ID,
+				RelatedRuleID: rules[other].ID,
+				Message:       fmt.Sprintf("duplicates rule %d", rules[other].ID),
+			}
+		}
+
+		if firewallRuleCovers(&normalized[other], &normalized[idx]) {
+			return &FirewallFinding{
+				GroupID:       groupID,
+				Kind:          FirewallFindingShadowed,
+				Severity:      FirewallSeverityMedium,
+				RuleID:        rules[idx].ID,
+				RelatedRuleID: rules[other].ID,
+				Message:       fmt.Sprintf("is already allowed by the broader rule %d", rules[other].ID),
+			}
+		}
+	}
+
+	return nil
+}
+
+// firewallRuleCovers reports whether every packet accepted by inner is also accepted by outer
+func firewallRuleCovers(outer, inner *FirewallRuleReq) bool {
+	if outer.Source != "" || inner.Source != "" || outer.IPType != inner.IPType || outer.Protocol != inner.Protocol {
+		return false
+	}
+
+	bits := ipv4PrefixBits
+	if outer.IPType == FirewallIPTypeV6 {
+		bits = ipv6PrefixBits
+	}
+
+	outerIP, innerIP := net.ParseIP(outer.Subnet), net.ParseIP(inner.Subnet)
+	if outerIP == nil || innerIP == nil || outer.SubnetSize > inner.SubnetSize {
+		return false
+	}
+	if !innerIP.Mask(net.CIDRMask(outer.SubnetSize, bits)).Equal(outerIP.Mask(net.CIDRMask(outer.SubnetSize, bits))) {
+		return false
+	}
+
+	outerLow, outerHigh := firewallPortBounds(outer.Port)
+	innerLow, innerHigh := firewallPortBounds(inner.Port)
+	return outerLow <= innerLow && innerHigh <= outerHigh
+}
+
+// firewallPortBounds returns the port range of a normalized rule, all ports when unset
+func firewallPortBounds(port string) (low, high int) {
+	if port == "" {
+		return 1, maxPort
+	}
+	if low, high, err := parsePortRange(port); err == nil {
+		return low, high
+	}
+	return 0, -1
+}
+
+// exposedRuleFinding reports rules that open sensitive ports to every address
+func exposedRuleFinding(groupID string, rule *FirewallRule, normalized *FirewallRuleReq) *FirewallFinding {
+	if normalized.Source != "" || normalized.SubnetSize != 0 {
+		return nil
+	}
+	if normalized.Protocol != FirewallProtocolTCP && normalized.Protocol != FirewallProtocolUDP {
+		return nil
+	}
+
+	low, high := firewallPortBounds(normalized.Port)
+
+	var exposed []int
+	for port := range sensitiveFirewallPorts {
+		if port >= low && port <= high {
+			exposed = append(exposed, port)
+		}
+	}
+	if len(exposed) == 0 {
+		return nil
+	}
+	sort.Ints(exposed)
+
+	services := make([]string, len(exposed))
+	for i, port := range exposed {
+		services[i] = fmt.Sprintf("%d (%s)", port, sensitiveFirewallPorts[port])
+	}
+
+	everyone := "0.0.0.0/0"
+	if normalized.IPType == FirewallIPTypeV6 {
+		everyone = "::/0"
+	}
+
+	return &FirewallFinding{
+		GroupID:  groupID,
+		Kind:     FirewallFindingExposed,
+		Severity: FirewallSeverityHigh,
+		RuleID:   rule.ID,
+		Message:  fmt.Sprintf("exposes %s to %s", strings.Join(services, ", "), everyone),
+	}
+}
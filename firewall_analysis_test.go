@@ -0,0 +1,81 @@
+package govultr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeFirewallRules(t *testing.T) {
+	group := &FirewallGroup{ID: "fw", InstanceCount: 0}
+	rules := []FirewallRule{
+		{ID: 1, IPType: "v4", Protocol: "tcp", Port: "22", Subnet: "0.0.0.0", SubnetSize: 0},
+		{ID: 2, IPType: "v4", Protocol: "tcp", Port: "22", Subnet: "192.0.2.0", SubnetSize: 24},
+		{ID: 3, IPType: "v4", Protocol: "tcp", Port: "80", Subnet: "198.51.100.0", SubnetSize: 24},
+		{ID: 4, IPType: "v4", Protocol: "tcp", Port: "80", Subnet: "198.51.100.0", SubnetSize: 24},
+		{ID: 5, IPType: "v6", Protocol: "tcp", Port: "3000:6000", Subnet: "::", SubnetSize: 0},
+		{ID: 6, IPType: "v4", Protocol: "tcp", Port: "8080", Subnet: "198.51.100.128", SubnetSize: 25},
+		{ID: 7, IPType: "v4", Protocol: "tcp", Port: "8000:9000", Subnet: "198.51.100.0", SubnetSize: 24},
+		{ID: 8, IPType: "v4", Protocol: "udp", Port: "53", Subnet: "0.0.0.0", SubnetSize: 0},
+		{ID: 9, IPType: "v4", Protocol: "tcp", Port: "22", Source: "cloudflare"},
+	}
+
+	findings := AnalyzeFirewallRules(group, rules)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%d %s %s %d", f.RuleID, f.Kind, f.Severity, f.RelatedRuleID))
+	}
+
+	expected := []string{
+		"0 unused low 0",
+		"1 exposed high 0",
+		"2 shadowed medium 1",
+		"4 duplicate low 3",
+		"5 exposed high 0",
+		"6 shadowed medium 7",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("AnalyzeFirewallRules returned %v, expected %v", got, expected)
+	}
+
+	if msg := findings[4].Message; msg != "exposes 3306 (MySQL), 3389 (RDP), 5432 (PostgreSQL) to ::/0" {
+		t.Errorf("AnalyzeFirewallRules exposed message = %q", msg)
+	}
+}
+
+func TestFireWallGroupServiceHandler_AnalyzeAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/firewalls", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_groups":[{"id":"a","instance_count":2},{"id":"b","instance_count":0}],"meta":{"total":2,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/firewalls/a/rules", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_rules":[
+			{"id":1,"ip_type":"v4","protocol":"tcp","port":"","subnet":"0.0.0.0","subnet_size":0}
+		],"meta":{"total":1,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/firewalls/b/rules", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_rules":[],"meta":{"total":0,"links":{}}}`)
+	})
+
+	report, err := client.FirewallGroup.AnalyzeAll(ctx)
+	if err != nil {
+		t.Fatalf("FirewallGroup.AnalyzeAll returned %+v", err)
+	}
+
+	if len(report.Groups) != 2 || len(report.Findings) != 2 {
+		t.Fatalf("FirewallGroup.AnalyzeAll returned %+v", report)
+	}
+
+	high := report.BySeverity(FirewallSeverityHigh)
+	if len(high) != 1 || high[0].GroupID != "a" {
+		t.Errorf("FirewallReport.BySeverity returned %+v", high)
+	}
+
+	if unused := report.Findings[1]; unused.GroupID != "b" || unused.Kind != FirewallFindingUnused {
+		t.Errorf("FirewallGroup.AnalyzeAll returned %+v for the unused group", unused)
+	}
+}
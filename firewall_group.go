@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -16,6 +17,12 @@ type FirewallGroupService interface { //nolint:dupl
 	Update(ctx context.Context, fwGroupID string, fwGroupReq *FirewallGroupReq) error
 	Delete(ctx context.Context, fwGroupID string) error
 	List(ctx context.Context, options *ListOptions) ([]FirewallGroup, *Meta, *http.Response, error)
+
+	ExportPolicy(ctx context.Context, fwGroupID string) (*FirewallPolicy, error)
+	ImportPolicy(ctx context.Context, policy *FirewallPolicy) (*FirewallGroup, error)
+
+	Analyze(ctx context.Context, fwGroupID string) (*FirewallReport, error)
+	AnalyzeAll(ctx context.Context) (*FirewallReport, error)
 }
 
 // FireWallGroupServiceHandler handles interaction with the firewall group methods for the Vultr API
@@ -34,6 +41,16 @@ type FirewallGroup struct {
 	MaxRuleCount  int    `json:"max_rule_count"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (f *FirewallGroup) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(f.DateCreated)
+}
+
+// ModifiedAt parses DateModified with ParseTimestamp
+func (f *FirewallGroup) ModifiedAt() (time.Time, error) {
+	return ParseTimestamp(f.DateModified)
+}
+
 // FirewallGroupReq struct is used to create and update a Firewall Group.
 type FirewallGroupReq struct {
 	Description string `json:"description"`
@@ -0,0 +1,603 @@
+package govultr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	firewallPolicyVersion = 1
+	iptablesDefaultChain  = "INPUT"
+)
+
+// FirewallPolicy is a portable description of a firewall group and its rules
+// that can be moved between groups, accounts and on-host firewalls
+type FirewallPolicy struct {
+	Version     int               `json:"version"`
+	Description string            `json:"description,omitempty"`
+	Rules       []FirewallRuleReq `json:"rules"`
+}
+
+// ExportPolicy reads a firewall group and its rules into a FirewallPolicy
+func (f *FireWallGroupServiceHandler) ExportPolicy(ctx context.Context, fwGroupID string) (*FirewallPolicy, error) {
+	group, _, err := f.Get(ctx, fwGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := listAll(func(options *ListOptions) ([]FirewallRule, *Meta, *http.Response, error) {
+		return f.client.FirewallRule.List(ctx, fwGroupID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &FirewallPolicy{Version: firewallPolicyVersion, Description: group.Description, Rules: []FirewallRuleReq{}}
+	for idx := range rules {
+		policy.Rules = append(policy.Rules, firewallRuleToReq(&rules[idx]))
+	}
+
+	return policy, nil
+}
+
+// ImportPolicy creates a new firewall group from a policy. If a rule cannot be
+// created the new group is deleted again.
+func (f *FireWallGroupServiceHandler) ImportPolicy(ctx context.Context, policy *FirewallPolicy) (*FirewallGroup, error) {
+	if policy.Version > firewallPolicyVersion {
+		return nil, fmt.Errorf("unsupported firewall policy version %d", policy.Version)
+	}
+
+	group, _, err := f.Create(ctx, &FirewallGroupReq{Description: policy.Description})
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range policy.Rules {
+		rule := normalizeFirewallRule(&policy.Rules[idx])
+		if _, _, err := f.client.FirewallRule.Create(ctx, group.ID, &rule); err != nil {
+			err = fmt.Errorf("creating %s: %w", describeFirewallRule(&rule), err)
+			if delErr := f.Delete(ctx, group.ID); delErr != nil {
+				return nil, fmt.Errorf("%w (deleting firewall group %s failed: %v)", err, group.ID, delErr)
+			}
+			return nil, err
+		}
+	}
+
+	return group, nil
+}
+
+// ReadFirewallPolicy reads a policy document in JSON or YAML format
+func ReadFirewallPolicy(r io.Reader) (*FirewallPolicy, error) {
+	policy := &FirewallPolicy{}
+	if err := readDocument(r, policy); err != nil {
+		return nil, fmt.Errorf("reading firewall policy: %w", err)
+	}
+
+	if policy.Version == 0 {
+		policy.Version = firewallPolicyVersion
+	}
+	return policy, nil
+}
+
+// JSON returns the policy as an indented JSON document
+func (p *FirewallPolicy) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// YAML returns the policy as a YAML document
+func (p *FirewallPolicy) YAML() ([]byte, error) {
+	return marshalYAML(p)
+}
+
+// IPTables renders the rules of one IP type ("v4" or "v6") in iptables-save
+// format for the filter table. Like a Vultr firewall group the chain drops
+// everything that is not explicitly accepted. Rules whose source is managed by
+// Vultr, such as cloudflare, cannot be expressed and are written as comments.
+func (p *FirewallPolicy) IPTables(ipType string) string {
+	var b strings.Builder
+	b.WriteString("*filter\n")
+	fmt.Fprintf(&b, ":%s DROP [0:0]\n", iptablesDefaultChain)
+	fmt.Fprintf(&b, "-A %s -i lo -j ACCEPT\n", iptablesDefaultChain)
+	fmt.Fprintf(&b, "-A %s -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT\n", iptablesDefaultChain)
+
+	for idx := range p.Rules {
+		rule := normalizeFirewallRule(&p.Rules[idx])
+		if rule.IPType != ipType {
+			continue
+		}
+		if rule.Source != "" {
+			fmt.Fprintf(&b, "# skipped %s: source %s is managed by Vultr\n", describeFirewallRule(&rule), rule.Source)
+			continue
+		}
+
+		args := []string{"-A", iptablesDefaultChain, "-p", iptablesProtocol(rule.Protocol, ipType)}
+		if rule.SubnetSize > 0 {
+			args = append(args, "-s", fmt.Sprintf("%s/%d", rule.Subnet, rule.SubnetSize))
+		}
+		if rule.Port != "" {
+			args = append(args, "--dport", rule.Port)
+		}
+		if rule.Notes != "" {
+			args = append(args, "-m", "comment", "--comment", strconv.Quote(rule.Notes))
+		}
+		args = append(args, "-j", "ACCEPT")
+		b.WriteString(strings.Join(args, " ") + "\n")
+	}
+
+	b.WriteString("COMMIT\n")
+	return b.String()
+}
+
+// NFTables renders the rules as an nftables ruleset with an inet table
+// covering both IPv4 and IPv6
+func (p *FirewallPolicy) NFTables(table string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table inet %s {\n", table)
+	b.WriteString("\tchain input {\n")
+	b.WriteString("\t\ttype filter hook input priority 0; policy drop;\n")
+	b.WriteString("\t\tiif lo accept\n")
+	b.WriteString("\t\tct state established,related accept\n")
+
+	for idx := range p.Rules {
+		rule := normalizeFirewallRule(&p.Rules[idx])
+		if rule.Source != "" {
+			fmt.Fprintf(&b, "\t\t# skipped %s: source %s is managed by Vultr\n", describeFirewallRule(&rule), rule.Source)
+			continue
+		}
+		b.WriteString("\t\t" + nftablesRule(&rule) + "\n")
+	}
+
+	b.WriteString("\t}\n}\n")
+	return b.String()
+}
+
+func nftablesRule(rule *FirewallRuleReq) string {
+	family := "ip"
+	if rule.IPType == FirewallIPTypeV6 {
+		family = "ip6"
+	}
+
+	var parts []string
+	if rule.SubnetSize > 0 {
+		parts = append(parts, fmt.Sprintf("%s saddr %s/%d", family, rule.Subnet, rule.SubnetSize))
+	}
+
+	switch rule.Protocol {
+	case FirewallProtocolTCP, FirewallProtocolUDP:
+		match := fmt.Sprintf("%s dport %s", rule.Protocol, strings.ReplaceAll(rule.Port, ":", "-"))
+		if rule.Port == "" {
+			match = fmt.Sprintf("meta l4proto %s", rule.Protocol)
+		}
+		if len(parts) == 0 {
+			parts = append(parts, fmt.Sprintf("meta nfproto ipv%s", strings.TrimPrefix(rule.IPType, "v")))
+		}
+		parts = append(parts, match)
+	case FirewallProtocolICMP:
+		if rule.IPType == FirewallIPTypeV6 {
+			parts = append(parts, "meta l4proto ipv6-icmp")
+		} else {
+			parts = append(parts, "meta l4proto icmp")
+		}
+	default:
+		if len(parts) == 0 {
+			parts = append(parts, fmt.Sprintf("meta nfproto ipv%s", strings.TrimPrefix(rule.IPType, "v")))
+		}
+		parts = append(parts, fmt.Sprintf("meta l4proto %s", rule.Protocol))
+	}
+
+	parts = append(parts, "accept")
+	if rule.Notes != "" {
+		parts = append(parts, "comment "+strconv.Quote(rule.Notes))
+	}
+	return strings.Join(parts, " ")
+}
+
+func iptablesProtocol(protocol FirewallProtocol, ipType string) string {
+	if protocol == FirewallProtocolICMP && ipType == FirewallIPTypeV6 {
+		return "ipv6-icmp"
+	}
+	return string(protocol)
+}
+
+// ParseIPTables reads ACCEPT rules from iptables-save output for the given IP
+// type. Only the INPUT chain is read and loopback and connection tracking
+// rules are ignored since Vultr firewall groups apply them implicitly.
+func ParseIPTables(r io.Reader, ipType string) (*FirewallPolicy, error) {
+	policy := &FirewallPolicy{Version: firewallPolicyVersion, Rules: []FirewallRuleReq{}}
+
+	scanner := bufio.NewScanner(r)
+	for num := 1; scanner.Scan(); num++ {
+		fields, err := splitShellWords(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("iptables line %d: %w", num, err)
+		}
+		if len(fields) < 2 || fields[0] != "-A" || fields[1] != iptablesDefaultChain {
+			continue
+		}
+
+		rule, err := parseIPTablesRule(fields[2:], ipType)
+		if err != nil {
+			return nil, fmt.Errorf("iptables line %d: %w", num, err)
+		}
+		if rule != nil {
+			policy.Rules = append(policy.Rules, *rule)
+		}
+	}
+
+	return policy, scanner.Err()
+}
+
+// iptablesOptionArgs is the number of arguments taken by each iptables option the
+// importer understands
+var iptablesOptionArgs = map[string]int{
+	"-p":                 1,
+	"--protocol":         1,
+	"-s":                 1,
+	"--source":           1,
+	"-i":                 1,
+	"--in-interface":     1,
+	"-m":                 1,
+	"--match":            1,
+	"--dport":            1,
+	"--destination-port": 1,
+	"--ctstate":          1,
+	"--state":            1,
+	"--comment":          1,
+	"-j":                 1,
+	"--jump":             1,
+}
+
+func parseIPTablesRule(args []string, ipType string) (*FirewallRuleReq, error) {
+	rule := &FirewallRuleReq{IPType: ipType, Subnet: "0.0.0.0"}
+	if ipType == FirewallIPTypeV6 {
+		rule.Subnet = "::"
+	}
+
+	target := ""
+	for i := 0; i < len(args); {
+		option := args[i]
+		if option == "!" {
+			return nil, fmt.Errorf("negated matches cannot be imported: %s", strings.Join(args[i:], " "))
+		}
+
+		count, ok := iptablesOptionArgs[option]
+		if !ok {
+			return nil, fmt.Errorf("unsupported iptables option %q", option)
+		}
+		if i+count >= len(args) {
+			return nil, fmt.Errorf("iptables option %q is missing its argument", option)
+		}
+		value := args[i+1]
+		i += 1 + count
+
+		switch option {
+		case "-j", "--jump":
+			target = value
+		case "-i", "--in-interface":
+			// loopback is implicit in Vultr firewall groups
+			if value == "lo" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("interface matches cannot be imported, found %q", value)
+		case "--ctstate", "--state":
+			// established and related traffic is implicit in Vultr firewall groups;
+			// rules for new connections are imported as plain rules
+			if !strings.Contains(value, "NEW") {
+				return nil, nil
+			}
+		default:
+			if err := applyIPTablesMatch(rule, option, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if target != "ACCEPT" {
+		return nil, fmt.Errorf("only ACCEPT rules can be imported, found %q", target)
+	}
+	if rule.Protocol == "" {
+		return nil, errors.New("rules must match a protocol")
+	}
+
+	normalized := normalizeFirewallRule(rule)
+	return &normalized, nil
+}
+
+func applyIPTablesMatch(rule *FirewallRuleReq, option, value string) error {
+	switch option {
+	case "-p", "--protocol":
+		rule.Protocol = FirewallProtocol(strings.ToLower(value))
+		if rule.Protocol == "ipv6-icmp" || rule.Protocol == "icmpv6" {
+			rule.Protocol = FirewallProtocolICMP
+		}
+	case "-s", "--source":
+		subnet, size, err := parseCIDR(value, rule.IPType)
+		if err != nil {
+			return err
+		}
+		rule.Subnet, rule.SubnetSize = subnet, size
+	case "--dport", "--destination-port":
+		rule.Port = value
+	case "--comment":
+		rule.Notes = value
+	case "-m", "--match":
+		switch value {
+		case "tcp", "udp", "icmp", "icmp6", "comment", "conntrack", "state":
+		default:
+			return fmt.Errorf("unsupported iptables match module %q", value)
+		}
+	}
+	return nil
+}
+
+func parseCIDR(value, ipType string) (subnet string, size int, err error) {
+	addr, bits, found := strings.Cut(value, "/")
+	size = ipv4PrefixBits
+	if ipType == FirewallIPTypeV6 {
+		size = ipv6PrefixBits
+	}
+
+	if found {
+		if size, err = strconv.Atoi(bits); err != nil {
+			return "", 0, fmt.Errorf("invalid prefix length in %q", value)
+		}
+	}
+	return addr, size, nil
+}
+
+// ParseNFTables reads accept rules from an nftables ruleset such as the output of
+// nft list ruleset or FirewallPolicy.NFTables. Only chains hooked to input are read,
+// counters are skipped and, as in ParseIPTables, loopback and connection tracking
+// rules are ignored. The address family of each rule comes from its table or, in an
+// inet table, from the rule's own matches.
+func ParseNFTables(r io.Reader) (*FirewallPolicy, error) {
+	policy := &FirewallPolicy{Version: firewallPolicyVersion, Rules: []FirewallRuleReq{}}
+	family, input := "", false
+
+	scanner := bufio.NewScanner(r)
+	for num := 1; scanner.Scan(); num++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields, err := splitShellWords(line)
+		if err != nil {
+			return nil, fmt.Errorf("nftables line %d: %w", num, err)
+		}
+
+		switch fields[0] {
+		case "table":
+			if len(fields) > 2 {
+				family = fields[1]
+			}
+		case "chain", "}":
+			input = false
+		case "type":
+			input = nftablesHook(fields) == "input"
+		default:
+			if !input {
+				continue
+			}
+
+			rule, err := parseNFTablesRule(fields, family)
+			if err != nil {
+				return nil, fmt.Errorf("nftables line %d: %w", num, err)
+			}
+			if rule != nil {
+				policy.Rules = append(policy.Rules, *rule)
+			}
+		}
+	}
+
+	return policy, scanner.Err()
+}
+
+func nftablesHook(fields []string) string {
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "hook" {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
+// nftablesFamilies maps the families of nftables tables to Vultr IP types;
+// rules in inet tables name their family themselves
+var nftablesFamilies = map[string]string{
+	"ip":  FirewallIPTypeV4,
+	"ip6": FirewallIPTypeV6,
+}
+
+func parseNFTablesRule(fields []string, family string) (*FirewallRuleReq, error) {
+	rule := &FirewallRuleReq{IPType: nftablesFamilies[family]}
+	verdict, implicit, err := applyNFTablesExprs(rule, nftablesFields(fields))
+	if err != nil || implicit {
+		return nil, err
+	}
+
+	if verdict != "accept" {
+		return nil, fmt.Errorf("only accept rules can be imported, found %q", verdict)
+	}
+	if rule.Protocol == "" {
+		return nil, errors.New("rules must match a protocol")
+	}
+	if rule.IPType == "" {
+		return nil, errors.New("rules in an inet table must match an address family")
+	}
+	if rule.Subnet == "" {
+		rule.Subnet = "0.0.0.0"
+		if rule.IPType == FirewallIPTypeV6 {
+			rule.Subnet = "::"
+		}
+	}
+
+	normalized := normalizeFirewallRule(rule)
+	return &normalized, nil
+}
+
+// applyNFTablesExprs applies the expressions of a rule and returns its verdict.
+// implicit is true for rules Vultr firewall groups apply implicitly.
+func applyNFTablesExprs(rule *FirewallRuleReq, fields []string) (verdict string, implicit bool, err error) {
+	for i := 0; i < len(fields); i++ {
+		expr := fields[i]
+		if expr == "accept" || expr == "drop" || expr == "reject" {
+			verdict = expr
+			continue
+		}
+		if i+1 >= len(fields) {
+			return "", false, fmt.Errorf("unsupported nftables expression %q", expr)
+		}
+
+		switch expr {
+		case "comment":
+			rule.Notes = fields[i+1]
+			i++
+		case "iif", "iifname":
+			// loopback is implicit in Vultr firewall groups
+			if fields[i+1] == "lo" {
+				return "", true, nil
+			}
+			return "", false, fmt.Errorf("interface matches cannot be imported: %s", strings.Join(fields[i:], " "))
+		default:
+			if i+2 >= len(fields) {
+				return "", false, fmt.Errorf("unsupported nftables expression %q", strings.Join(fields[i:], " "))
+			}
+			if implicit, err := applyNFTablesExpr(rule, expr+" "+fields[i+1], fields[i+2]); err != nil || implicit {
+				return "", implicit, err
+			}
+			i += 2
+		}
+	}
+	return verdict, false, nil
+}
+
+func applyNFTablesExpr(rule *FirewallRuleReq, match, value string) (implicit bool, err error) {
+	switch {
+	case value == "!=":
+		return false, fmt.Errorf("negated matches cannot be imported: %s", match)
+	case match == "ct state":
+		// established and related traffic is implicit in Vultr firewall groups;
+		// rules for new connections are imported as plain rules
+		return !strings.Contains(value, "new"), nil
+	case strings.HasPrefix(value, "{"):
+		return false, fmt.Errorf("sets cannot be imported: %s %s", match, value)
+	default:
+		return false, applyNFTablesMatch(rule, match, value)
+	}
+}
+
+// nftablesFields drops counters and joins the elements of anonymous sets such as
+// { 80, 443 } into a single field so a set is read as one match value
+func nftablesFields(fields []string) []string {
+	joined := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "counter":
+			if i+1 < len(fields) && fields[i+1] == "packets" {
+				i += 4
+			}
+		case "{":
+			set := fields[i]
+			for i++; i < len(fields) && fields[i] != "}"; i++ {
+				set += strings.TrimSuffix(fields[i], ",") + ","
+			}
+			joined = append(joined, strings.TrimSuffix(set, ",")+"}")
+		default:
+			joined = append(joined, fields[i])
+		}
+	}
+	return joined
+}
+
+func applyNFTablesMatch(rule *FirewallRuleReq, match, value string) error {
+	switch match {
+	case "ip saddr", "ip6 saddr":
+		ipType := FirewallIPTypeV4
+		if match == "ip6 saddr" {
+			ipType = FirewallIPTypeV6
+		}
+		if err := setNFTablesIPType(rule, ipType); err != nil {
+			return err
+		}
+
+		subnet, size, err := parseCIDR(value, ipType)
+		if err != nil {
+			return err
+		}
+		rule.Subnet, rule.SubnetSize = subnet, size
+	case "meta nfproto":
+		return setNFTablesIPType(rule, strings.TrimPrefix(value, "ip"))
+	case "meta l4proto":
+		switch value {
+		case "icmp":
+			rule.Protocol = FirewallProtocolICMP
+			return setNFTablesIPType(rule, FirewallIPTypeV4)
+		case "ipv6-icmp", "icmpv6":
+			rule.Protocol = FirewallProtocolICMP
+			return setNFTablesIPType(rule, FirewallIPTypeV6)
+		default:
+			rule.Protocol = FirewallProtocol(value)
+		}
+	case "tcp dport", "udp dport":
+		rule.Protocol = FirewallProtocol(strings.TrimSuffix(match, " dport"))
+		rule.Port = value
+	default:
+		return fmt.Errorf("unsupported nftables match %q", match)
+	}
+	return nil
+}
+
+func setNFTablesIPType(rule *FirewallRuleReq, ipType string) error {
+	if ipType != FirewallIPTypeV4 && ipType != FirewallIPTypeV6 {
+		return fmt.Errorf("unsupported nftables address family %q", ipType)
+	}
+	if rule.IPType != "" && rule.IPType != ipType {
+		return fmt.Errorf("rule matches both %s and %s addresses", rule.IPType, ipType)
+	}
+	rule.IPType = ipType
+	return nil
+}
+
+// splitShellWords splits a line on spaces, honouring double quotes as iptables-save writes them
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord, quoted := false, false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			i++
+			word.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
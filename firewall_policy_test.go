@@ -0,0 +1,362 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testFirewallPolicy = &FirewallPolicy{
+	Version:     1,
+	Description: "web servers",
+	Rules: []FirewallRuleReq{
+		{IPType: "v4", Protocol: "tcp", Subnet: "192.0.2.0", SubnetSize: 24, Port: "22", Notes: "office ssh"},
+		{IPType: "v4", Protocol: "tcp", Subnet: "0.0.0.0", SubnetSize: 0, Port: "8000:9000"},
+		{IPType: "v6", Protocol: "icmp", Subnet: "::", SubnetSize: 0},
+		{IPType: "v4", Protocol: "tcp", Port: "443", Source: "cloudflare"},
+	},
+}
+
+func TestFireWallGroupServiceHandler_ExportPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/firewalls/fw", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_group":{"id":"fw","description":"web servers"}}`)
+	})
+	mux.HandleFunc("/v2/firewalls/fw/rules", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_rules":[
+			{"id":1,"ip_type":"v4","action":"accept","protocol":"tcp","port":"22","subnet":"192.0.2.0","subnet_size":24,"notes":"office ssh"},
+			{"id":2,"ip_type":"v4","action":"accept","protocol":"tcp","port":"8000:9000","subnet":"0.0.0.0","subnet_size":0},
+			{"id":3,"ip_type":"v6","action":"accept","protocol":"icmp","port":"","subnet":"::","subnet_size":0},
+			{"id":4,"ip_type":"v4","action":"accept","protocol":"tcp","port":"443","subnet":"","subnet_size":0,"source":"cloudflare"}
+		],"meta":{"total":4,"links":{}}}`)
+	})
+
+	policy, err := client.FirewallGroup.ExportPolicy(ctx, "fw")
+	if err != nil {
+		t.Fatalf("FirewallGroup.ExportPolicy returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(policy, testFirewallPolicy) {
+		t.Errorf("FirewallGroup.ExportPolicy returned %+v, expected %+v", policy, testFirewallPolicy)
+	}
+}
+
+func TestFireWallGroupServiceHandler_ImportPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/firewalls", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_group":{"id":"new","description":"web servers"}}`)
+	})
+
+	var created []FirewallRuleReq
+	mux.HandleFunc("/v2/firewalls/new/rules", func(writer http.ResponseWriter, request *http.Request) {
+		var req FirewallRuleReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, req)
+		fmt.Fprintf(writer, `{"firewall_rule":{"id":%d}}`, len(created))
+	})
+
+	group, err := client.FirewallGroup.ImportPolicy(ctx, testFirewallPolicy)
+	if err != nil {
+		t.Fatalf("FirewallGroup.ImportPolicy returned %+v", err)
+	}
+
+	if group.ID != "new" || !reflect.DeepEqual(created, testFirewallPolicy.Rules) {
+		t.Errorf("FirewallGroup.ImportPolicy created %+v in %+v", created, group)
+	}
+}
+
+func TestFireWallGroupServiceHandler_ImportPolicyCleanup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/firewalls", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_group":{"id":"new"}}`)
+	})
+	mux.HandleFunc("/v2/firewalls/new/rules", func(writer http.ResponseWriter, request *http.Request) {
+		http.Error(writer, `{"error":"too many rules"}`, http.StatusBadRequest)
+	})
+
+	deleted := false
+	mux.HandleFunc("/v2/firewalls/new", func(writer http.ResponseWriter, request *http.Request) {
+		deleted = request.Method == http.MethodDelete
+	})
+
+	if _, err := client.FirewallGroup.ImportPolicy(ctx, testFirewallPolicy); err == nil {
+		t.Fatal("FirewallGroup.ImportPolicy expected an error")
+	}
+	if !deleted {
+		t.Error("FirewallGroup.ImportPolicy did not delete the partially imported group")
+	}
+}
+
+func TestFirewallPolicy_Documents(t *testing.T) {
+	encoders := map[string]func() ([]byte, error){
+		"json": testFirewallPolicy.JSON,
+		"yaml": testFirewallPolicy.YAML,
+	}
+
+	for name, encode := range encoders {
+		data, err := encode()
+		if err != nil {
+			t.Fatalf("FirewallPolicy %s returned %+v", name, err)
+		}
+
+		policy, err := ReadFirewallPolicy(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadFirewallPolicy(%s) returned %+v", name, err)
+		}
+		if !reflect.DeepEqual(policy, testFirewallPolicy) {
+			t.Errorf("ReadFirewallPolicy(%s) returned %+v, expected %+v", name, policy, testFirewallPolicy)
+		}
+	}
+
+	handwritten := `description: db
+rules:
+  - ip_type: v4
+    protocol: tcp
+    subnet: 10.0.0.0
+    subnet_size: 8
+    port: 5432
+`
+	policy, err := ReadFirewallPolicy(strings.NewReader(handwritten))
+	if err != nil {
+		t.Fatalf("ReadFirewallPolicy returned %+v", err)
+	}
+	if policy.Version != 1 || len(policy.Rules) != 1 || policy.Rules[0].Port != "5432" {
+		t.Errorf("ReadFirewallPolicy returned %+v", policy)
+	}
+}
+
+func TestFirewallPolicy_IPTables(t *testing.T) {
+	rules := testFirewallPolicy.IPTables(FirewallIPTypeV4)
+
+	expected := `*filter
+:INPUT DROP [0:0]
+-A INPUT -i lo -j ACCEPT
+-A INPUT -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT
+-A INPUT -p tcp -s 192.0.2.0/24 --dport 22 -m comment --comment "office ssh" -j ACCEPT
+-A INPUT -p tcp --dport 8000:9000 -j ACCEPT
+# skipped v4 tcp from cloudflare port 443: source cloudflare is managed by Vultr
+COMMIT
+`
+	if rules != expected {
+		t.Errorf("FirewallPolicy.IPTables returned\n%s\nexpected\n%s", rules, expected)
+	}
+
+	policy, err := ParseIPTables(strings.NewReader(rules), FirewallIPTypeV4)
+	if err != nil {
+		t.Fatalf("ParseIPTables returned %+v", err)
+	}
+	if !reflect.DeepEqual(policy.Rules, testFirewallPolicy.Rules[:2]) {
+		t.Errorf("ParseIPTables returned %+v, expected %+v", policy.Rules, testFirewallPolicy.Rules[:2])
+	}
+
+	v6, err := ParseIPTables(strings.NewReader(testFirewallPolicy.IPTables(FirewallIPTypeV6)), FirewallIPTypeV6)
+	if err != nil {
+		t.Fatalf("ParseIPTables returned %+v", err)
+	}
+	if !reflect.DeepEqual(v6.Rules, testFirewallPolicy.Rules[2:3]) {
+		t.Errorf("ParseIPTables returned %+v, expected %+v", v6.Rules, testFirewallPolicy.Rules[2:3])
+	}
+
+	if _, err := ParseIPTables(strings.NewReader("-A INPUT -p tcp --dport 23 -j DROP\n"), FirewallIPTypeV4); err == nil {
+		t.Error("ParseIPTables expected an error for a DROP rule")
+	}
+}
+
+func TestParseIPTables_Options(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		rules []FirewallRuleReq
+		err   string
+	}{
+		{
+			name: "new connections",
+			line: "-A INPUT -p tcp -m tcp --dport 22 -m conntrack --ctstate NEW -j ACCEPT",
+			rules: []FirewallRuleReq{
+				{IPType: FirewallIPTypeV4, Protocol: FirewallProtocolTCP, Subnet: "0.0.0.0", Port: "22"},
+			},
+		},
+		{
+			name:  "established connections",
+			line:  "-A INPUT -m state --state ESTABLISHED,RELATED -j ACCEPT",
+			rules: []FirewallRuleReq{},
+		},
+		{
+			name: "negated source",
+			line: "-A INPUT ! -s 192.0.2.0/24 -p tcp --dport 22 -j ACCEPT",
+			err:  "negated matches cannot be imported",
+		},
+		{
+			name: "negated interface",
+			line: "-A INPUT -p tcp ! -i eth1 -j ACCEPT",
+			err:  "negated matches cannot be imported",
+		},
+		{
+			name: "unknown option",
+			line: "-A INPUT -p tcp -d 192.0.2.1 --dport 22 -j ACCEPT",
+			err:  `unsupported iptables option "-d"`,
+		},
+		{
+			name: "unknown module",
+			line: "-A INPUT -p tcp -m multiport --dports 80,443 -j ACCEPT",
+			err:  `unsupported iptables match module "multiport"`,
+		},
+		{
+			name: "missing argument",
+			line: "-A INPUT -p tcp --dport",
+			err:  `iptables option "--dport" is missing its argument`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParseIPTables(strings.NewReader(tt.line+"\n"), FirewallIPTypeV4)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseIPTables returned %v, expected an error containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIPTables returned %+v", err)
+			}
+			if !reflect.DeepEqual(policy.Rules, tt.rules) {
+				t.Errorf("ParseIPTables returned %+v, expected %+v", policy.Rules, tt.rules)
+			}
+		})
+	}
+}
+
+func TestFirewallPolicy_NFTables(t *testing.T) {
+	expected := `table inet vultr {
+	chain input {
+		type filter hook input priority 0; policy drop;
+		iif lo accept
+		ct state established,related accept
+		ip saddr 192.0.2.0/24 tcp dport 22 accept comment "office ssh"
+		meta nfproto ipv4 tcp dport 8000-9000 accept
+		meta l4proto ipv6-icmp accept
+		# skipped v4 tcp from cloudflare port 443: source cloudflare is managed by Vultr
+	}
+}
+`
+	rules := testFirewallPolicy.NFTables("vultr")
+	if rules != expected {
+		t.Errorf("FirewallPolicy.NFTables returned\n%s\nexpected\n%s", rules, expected)
+	}
+
+	policy, err := ParseNFTables(strings.NewReader(rules))
+	if err != nil {
+		t.Fatalf("ParseNFTables returned %+v", err)
+	}
+	if !reflect.DeepEqual(policy.Rules, testFirewallPolicy.Rules[:3]) {
+		t.Errorf("ParseNFTables returned %+v, expected %+v", policy.Rules, testFirewallPolicy.Rules[:3])
+	}
+}
+
+func TestParseNFTables_Options(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		rule  string
+		rules []FirewallRuleReq
+		err   string
+	}{
+		{
+			name:  "nft list ruleset",
+			table: "inet",
+			rule:  `ct state { established, related } counter packets 10 bytes 800 accept`,
+			rules: []FirewallRuleReq{},
+		},
+		{
+			name:  "new connections",
+			table: "ip",
+			rule:  `ct state new tcp dport 22 counter packets 0 bytes 0 accept comment "ssh"`,
+			rules: []FirewallRuleReq{
+				{IPType: FirewallIPTypeV4, Protocol: FirewallProtocolTCP, Subnet: "0.0.0.0", Port: "22", Notes: "ssh"},
+			},
+		},
+		{
+			name:  "ip6 table",
+			table: "ip6",
+			rule:  "ip6 saddr 2001:db8::/32 udp dport 53 accept",
+			rules: []FirewallRuleReq{
+				{IPType: FirewallIPTypeV6, Protocol: FirewallProtocolUDP, Subnet: "2001:db8::", SubnetSize: 32, Port: "53"},
+			},
+		},
+		{
+			name:  "loopback",
+			table: "inet",
+			rule:  `iifname "lo" accept`,
+			rules: []FirewallRuleReq{},
+		},
+		{
+			name:  "no family",
+			table: "inet",
+			rule:  "tcp dport 22 accept",
+			err:   "rules in an inet table must match an address family",
+		},
+		{
+			name:  "mixed families",
+			table: "ip",
+			rule:  "meta l4proto ipv6-icmp accept",
+			err:   "rule matches both v4 and v6 addresses",
+		},
+		{
+			name:  "negated source",
+			table: "ip",
+			rule:  "ip saddr != 192.0.2.0/24 tcp dport 22 accept",
+			err:   "negated matches cannot be imported",
+		},
+		{
+			name:  "set",
+			table: "ip",
+			rule:  "tcp dport { 80, 443 } accept",
+			err:   "sets cannot be imported: tcp dport {80,443}",
+		},
+		{
+			name:  "interface",
+			table: "ip",
+			rule:  "iif eth1 tcp dport 22 accept",
+			err:   "interface matches cannot be imported",
+		},
+		{
+			name:  "drop",
+			table: "ip",
+			rule:  "tcp dport 23 drop",
+			err:   `only accept rules can be imported, found "drop"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset := fmt.Sprintf("table %s filter {\n\tchain forward {\n\t\ttype filter hook forward priority 0;\n\t\ttcp dport 23 drop\n\t}\n"+
+				"\tchain input {\n\t\ttype filter hook input priority 0; policy drop;\n\t\t%s\n\t}\n}\n", tt.table, tt.rule)
+			policy, err := ParseNFTables(strings.NewReader(ruleset))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseNFTables returned %v, expected an error containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNFTables returned %+v", err)
+			}
+			if !reflect.DeepEqual(policy.Rules, tt.rules) {
+				t.Errorf("ParseNFTables returned %+v, expected %+v", policy.Rules, tt.rules)
+			}
+		})
+	}
+}
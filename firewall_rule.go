@@ -3,7 +3,10 @@ package govultr
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/go-querystring/query"
 )
@@ -15,6 +18,8 @@ type FireWallRuleService interface {
 	Get(ctx context.Context, fwGroupID string, fwRuleID int) (*FirewallRule, *http.Response, error)
 	Delete(ctx context.Context, fwGroupID string, fwRuleID int) error
 	List(ctx context.Context, fwGroupID string, options *ListOptions) ([]FirewallRule, *Meta, *http.Response, error)
+
+	SyncFirewallRules(ctx context.Context, fwGroupID string, desired []FirewallRuleReq) (*FirewallRuleSyncPlan, error)
 }
 
 // FireWallRuleServiceHandler handles interaction with the firewall rule methods for the Vultr API
@@ -24,26 +29,140 @@ type FireWallRuleServiceHandler struct {
 
 // FirewallRule represents a Vultr firewall rule
 type FirewallRule struct {
-	ID         int    `json:"id"`
-	Action     string `json:"action"`
-	IPType     string `json:"ip_type"`
-	Protocol   string `json:"protocol"`
-	Port       string `json:"port"`
-	Subnet     string `json:"subnet"`
-	SubnetSize int    `json:"subnet_size"`
-	Source     string `json:"source"`
-	Notes      string `json:"notes"`
+	ID         int              `json:"id"`
+	Action     string           `json:"action"`
+	IPType     string           `json:"ip_type"`
+	Protocol   FirewallProtocol `json:"protocol"`
+	Port       string           `json:"port"`
+	Subnet     string           `json:"subnet"`
+	SubnetSize int              `json:"subnet_size"`
+	Source     string           `json:"source"`
+	Notes      string           `json:"notes"`
 }
 
 // FirewallRuleReq struct used to create a FirewallRule.
 type FirewallRuleReq struct {
-	IPType     string `json:"ip_type"`
-	Protocol   string `json:"protocol"`
-	Subnet     string `json:"subnet"`
-	SubnetSize int    `json:"subnet_size"`
-	Port       string `json:"port,omitempty"`
-	Source     string `json:"source,omitempty"`
-	Notes      string `json:"notes,omitempty"`
+	IPType     string           `json:"ip_type"`
+	Protocol   FirewallProtocol `json:"protocol"`
+	Subnet     string           `json:"subnet"`
+	SubnetSize int              `json:"subnet_size"`
+	Port       string           `json:"port,omitempty"`
+	Source     string           `json:"source,omitempty"`
+	Notes      string           `json:"notes,omitempty"`
+}
+
+// Firewall rule IP types accepted by the API
+const (
+	FirewallIPTypeV4 = "v4"
+	FirewallIPTypeV6 = "v6"
+)
+
+const (
+	maxPort         = 65535
+	ipv4PrefixBits  = 32
+	ipv6PrefixBits  = 128
+	portRangeFields = 2
+)
+
+// Validate checks the request for mistakes the API would otherwise reject
+func (f *FirewallRuleReq) Validate() error {
+	v := newValidation("FirewallRuleReq")
+
+	bits := ipv4PrefixBits
+	switch f.IPType {
+	case FirewallIPTypeV4:
+	case FirewallIPTypeV6:
+		bits = ipv6PrefixBits
+	default:
+		v.addf("ip_type", "must be %s or %s", FirewallIPTypeV4, FirewallIPTypeV6)
+	}
+
+	if !f.Protocol.Valid() {
+		v.addf("protocol", "unknown protocol %q", f.Protocol)
+	}
+
+	// Rules with a source such as cloudflare or a load balancer take their subnets from it
+	if f.Source == "" || f.Subnet != "" {
+		ip := net.ParseIP(f.Subnet)
+		sizeOK := f.SubnetSize >= 0 && f.SubnetSize <= bits
+		if !sizeOK {
+			v.addf("subnet_size", "must be between 0 and %d", bits)
+		}
+
+		switch {
+		case ip == nil:
+			v.addf("subnet", "%q is not an IP address", f.Subnet)
+		case (ip.To4() != nil) != (f.IPType == FirewallIPTypeV4):
+			v.addf("subnet", "%q does not match ip_type %s", f.Subnet, f.IPType)
+		case sizeOK && !ip.Equal(ip.Mask(net.CIDRMask(f.SubnetSize, bits))):
+			v.addf("subnet", "%s/%d has host bits set", f.Subnet, f.SubnetSize)
+		}
+	}
+
+	switch {
+	case f.Port == "":
+	case f.Protocol != FirewallProtocolTCP && f.Protocol != FirewallProtocolUDP:
+		v.addf("port", "is only supported for tcp and udp rules")
+	default:
+		if _, _, err := parsePortRange(f.Port); err != nil {
+			v.addf("port", "%s", err)
+		}
+	}
+
+	return v.err()
+}
+
+// parsePortRange parses a single port or a low:high port range
+func parsePortRange(port string) (low, high int, err error) {
+	parts := strings.Split(port, ":")
+	if len(parts) > portRangeFields {
+		return 0, 0, fmt.Errorf("invalid port range %q", port)
+	}
+
+	low, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port %q", port)
+	}
+
+	high = low
+	if len(parts) == portRangeFields {
+		if high, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return 0, 0, fmt.Errorf("invalid port %q", port)
+		}
+	}
+
+	if low < 1 || high > maxPort || low > high {
+		return 0, 0, fmt.Errorf("invalid port range %q", port)
+	}
+
+	return low, high, nil
+}
+
+// FirewallProtocol is the network protocol matched by a firewall rule
+type FirewallProtocol string
+
+// Firewall rule protocols accepted by the API
+const (
+	FirewallProtocolICMP FirewallProtocol = "icmp"
+	FirewallProtocolTCP  FirewallProtocol = "tcp"
+	FirewallProtocolUDP  FirewallProtocol = "udp"
+	FirewallProtocolGRE  FirewallProtocol = "gre"
+	FirewallProtocolESP  FirewallProtocol = "esp"
+	FirewallProtocolAH   FirewallProtocol = "ah"
+)
+
+// Valid reports whether the protocol is one known to this version of govultr
+func (p FirewallProtocol) Valid() bool {
+	switch p {
+	case FirewallProtocolICMP, FirewallProtocolTCP, FirewallProtocolUDP, FirewallProtocolGRE, FirewallProtocolESP, FirewallProtocolAH:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (p FirewallProtocol) String() string {
+	return string(p)
 }
 
 type firewallRulesBase struct {
@@ -57,6 +176,10 @@ type firewallRuleBase struct {
 
 // Create will create a rule in a firewall group.
 func (f *FireWallRuleServiceHandler) Create(ctx context.Context, fwGroupID string, fwRuleReq *FirewallRuleReq) (*FirewallRule, *http.Response, error) { //nolint:lll
+	if err := f.client.validate(fwRuleReq); err != nil {
+		return nil, nil, err
+	}
+
 	uri := fmt.Sprintf("/v2/firewalls/%s/rules", fwGroupID)
 
 	req, err := f.client.NewRequest(ctx, http.MethodPost, uri, fwRuleReq)
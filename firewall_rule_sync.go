@@ -0,0 +1,212 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FirewallRuleSyncPlan lists the changes needed to converge a firewall group to its desired rules
+type FirewallRuleSyncPlan struct {
+	GroupID   string
+	Creates   []FirewallRuleReq
+	Deletes   []FirewallRule
+	Unchanged []FirewallRule
+}
+
+// Empty reports whether the group already matches the desired rules
+func (f *FirewallRuleSyncPlan) Empty() bool {
+	return len(f.Creates) == 0 && len(f.Deletes) == 0
+}
+
+// String renders the plan for people to review
+func (f *FirewallRuleSyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for firewall group %s: %d to create, %d to delete, %d unchanged\n",
+		f.GroupID, len(f.Creates), len(f.Deletes), len(f.Unchanged))
+
+	for idx := range f.Creates {
+		fmt.Fprintf(&b, "  + %s\n", describeFirewallRule(&f.Creates[idx]))
+	}
+	for idx := range f.Deletes {
+		req := firewallRuleToReq(&f.Deletes[idx])
+		fmt.Fprintf(&b, "  - %s (rule %d)\n", describeFirewallRule(&req), f.Deletes[idx].ID)
+	}
+
+	return b.String()
+}
+
+func describeFirewallRule(r *FirewallRuleReq) string {
+	from := fmt.Sprintf("%s/%d", r.Subnet, r.SubnetSize)
+	if r.Source != "" {
+		from = r.Source
+	}
+
+	port := ""
+	if r.Port != "" {
+		port = " port " + r.Port
+	}
+
+	return fmt.Sprintf("%s %s from %s%s", r.IPType, r.Protocol, from, port)
+}
+
+// SyncFirewallRules converges the rules of a firewall group to desired. Rules are
+// compared after normalization, so "8000-9000" matches "8000:9000" and the IP type
+// is derived from the subnet when omitted; notes are not compared. Missing rules
+// are created before stale rules are deleted so traffic that is allowed before and
+// after the sync is never blocked. If a create fails the rules created so far are
+// removed again and nothing is deleted.
+func (f *FireWallRuleServiceHandler) SyncFirewallRules(ctx context.Context, fwGroupID string, desired []FirewallRuleReq) (*FirewallRuleSyncPlan, error) { //nolint:lll
+	current, err := listAll(func(options *ListOptions) ([]FirewallRule, *Meta, *http.Response, error) {
+		return f.List(ctx, fwGroupID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planFirewallRuleSync(fwGroupID, current, desired)
+	if plan.Empty() {
+		return plan, nil
+	}
+
+	return plan, f.applyFirewallRuleSync(ctx, plan)
+}
+
+func planFirewallRuleSync(fwGroupID string, current []FirewallRule, desired []FirewallRuleReq) *FirewallRuleSyncPlan {
+	plan := &FirewallRuleSyncPlan{GroupID: fwGroupID}
+
+	wanted := make(map[string]bool, len(desired))
+	for idx := range desired {
+		rule := normalizeFirewallRule(&desired[idx])
+		key := firewallRuleKey(&rule)
+		if _, ok := wanted[key]; ok {
+			continue
+		}
+		wanted[key] = false
+		plan.Creates = append(plan.Creates, rule)
+	}
+
+	for idx := range current {
+		rule := firewallRuleToReq(&current[idx])
+		key := firewallRuleKey(&rule)
+		if matched, ok := wanted[key]; ok && !matched {
+			wanted[key] = true
+			plan.Unchanged = append(plan.Unchanged, current[idx])
+			continue
+		}
+		plan.Deletes = append(plan.Deletes, current[idx])
+	}
+
+	creates := plan.Creates[:0]
+	for idx := range plan.Creates {
+		if !wanted[firewallRuleKey(&plan.Creates[idx])] {
+			creates = append(creates, plan.Creates[idx])
+		}
+	}
+	plan.Creates = creates
+
+	return plan
+}
+
+func (f *FireWallRuleServiceHandler) applyFirewallRuleSync(ctx context.Context, plan *FirewallRuleSyncPlan) error {
+	var created []int
+
+	for idx := range plan.Creates {
+		rule := &plan.Creates[idx]
+		newRule, _, err := f.Create(ctx, plan.GroupID, rule)
+		if err != nil {
+			err = fmt.Errorf("creating %s: %w", describeFirewallRule(rule), err)
+
+			var errs []error
+			for i := len(created) - 1; i >= 0; i-- {
+				if rbErr := f.Delete(ctx, plan.GroupID, created[i]); rbErr != nil {
+					errs = append(errs, rbErr)
+				}
+			}
+			if rbErr := errors.Join(errs...); rbErr != nil {
+				return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+			return err
+		}
+		created = append(created, newRule.ID)
+	}
+
+	for idx := range plan.Deletes {
+		if err := f.Delete(ctx, plan.GroupID, plan.Deletes[idx].ID); err != nil {
+			return fmt.Errorf("deleting rule %d: %w", plan.Deletes[idx].ID, err)
+		}
+	}
+
+	return nil
+}
+
+func firewallRuleToReq(rule *FirewallRule) FirewallRuleReq {
+	return normalizeFirewallRule(&FirewallRuleReq{
+		IPType:     rule.IPType,
+		Protocol:   rule.Protocol,
+		Subnet:     rule.Subnet,
+		SubnetSize: rule.SubnetSize,
+		Port:       rule.Port,
+		Source:     rule.Source,
+		Notes:      rule.Notes,
+	})
+}
+
+// normalizeFirewallRule returns the canonical form of a rule used to compare rules
+func normalizeFirewallRule(rule *FirewallRuleReq) FirewallRuleReq {
+	normalized := *rule
+	normalized.Protocol = FirewallProtocol(strings.ToLower(strings.TrimSpace(string(rule.Protocol))))
+	normalized.IPType = strings.ToLower(strings.TrimSpace(rule.IPType))
+	normalized.Source = strings.ToLower(strings.TrimSpace(rule.Source))
+
+	if ip := net.ParseIP(strings.TrimSpace(rule.Subnet)); ip != nil {
+		normalized.Subnet = ip.String()
+		if normalized.IPType == "" {
+			normalized.IPType = FirewallIPTypeV6
+			if ip.To4() != nil {
+				normalized.IPType = FirewallIPTypeV4
+			}
+		}
+	}
+
+	normalized.Port = normalizeFirewallPort(normalized.Protocol, rule.Port)
+
+	return normalized
+}
+
+// normalizeFirewallPort rewrites ranges as low:high, collapses single port ranges and
+// treats the full range as no port restriction
+func normalizeFirewallPort(protocol FirewallProtocol, port string) string {
+	if protocol != FirewallProtocolTCP && protocol != FirewallProtocolUDP {
+		return ""
+	}
+
+	port = strings.ReplaceAll(strings.TrimSpace(port), "-", ":")
+	if port == "" {
+		return ""
+	}
+
+	low, high, err := parsePortRange(port)
+	switch {
+	case err != nil:
+		return port
+	case low == 1 && high == maxPort:
+		return ""
+	case low == high:
+		return strconv.Itoa(low)
+	default:
+		return fmt.Sprintf("%d:%d", low, high)
+	}
+}
+
+func firewallRuleKey(rule *FirewallRuleReq) string {
+	from := fmt.Sprintf("%s/%d", rule.Subnet, rule.SubnetSize)
+	if rule.Source != "" {
+		from = "source=" + rule.Source
+	}
+	return strings.Join([]string{rule.IPType, string(rule.Protocol), from, rule.Port}, "|")
+}
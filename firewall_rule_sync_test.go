@@ -0,0 +1,138 @@
+package govultr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const syncFirewallRulesResponse = `{"firewall_rules":[
+	{"id":1,"ip_type":"v4","action":"accept","protocol":"tcp","port":"22","subnet":"192.0.2.0","subnet_size":24},
+	{"id":2,"ip_type":"v4","action":"accept","protocol":"tcp","port":"8000:9000","subnet":"0.0.0.0","subnet_size":0},
+	{"id":3,"ip_type":"v6","action":"accept","protocol":"tcp","port":"443","subnet":"::","subnet_size":0},
+	{"id":4,"ip_type":"v4","action":"accept","protocol":"tcp","port":"80","subnet":"","subnet_size":0,"source":"cloudflare"},
+	{"id":5,"ip_type":"v4","action":"accept","protocol":"tcp","port":"22","subnet":"192.0.2.0","subnet_size":24}
+],"meta":{"total":5,"links":{}}}`
+
+var syncDesiredFirewallRules = []FirewallRuleReq{
+	{Protocol: "TCP", Port: "22", Subnet: "192.0.2.0", SubnetSize: 24},
+	{IPType: "v4", Protocol: "tcp", Port: "8000-9000", Subnet: "0.0.0.0", SubnetSize: 0, Notes: "app"},
+	{IPType: "v4", Protocol: "tcp", Port: "80", Source: "Cloudflare"},
+	{IPType: "v6", Protocol: "udp", Port: "53:53", Subnet: "2001:DB8::", SubnetSize: 32},
+	{IPType: "v4", Protocol: "icmp", Port: "1", Subnet: "0.0.0.0", SubnetSize: 0},
+}
+
+type firewallSyncRecorder struct {
+	mu     sync.Mutex
+	calls  []string
+	failOn string
+	nextID int
+}
+
+func (r *firewallSyncRecorder) register(t *testing.T) {
+	mux.HandleFunc("/v2/firewalls/fw/rules", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, syncFirewallRulesResponse)
+			return
+		}
+
+		var req FirewallRuleReq
+		if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		call := fmt.Sprintf("create %s %s %s/%d %s", req.IPType, req.Protocol, req.Subnet, req.SubnetSize, req.Port)
+		r.calls = append(r.calls, call)
+		if call == r.failOn {
+			http.Error(writer, `{"error":"boom"}`, http.StatusBadRequest)
+			return
+		}
+		r.nextID++
+		fmt.Fprintf(writer, `{"firewall_rule":{"id":%d}}`, 100+r.nextID)
+	})
+
+	mux.HandleFunc("/v2/firewalls/fw/rules/", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodDelete)
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, "delete "+strings.TrimPrefix(request.URL.Path, "/v2/firewalls/fw/rules/"))
+	})
+}
+
+func TestFireWallRuleServiceHandler_SyncFirewallRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &firewallSyncRecorder{}
+	recorder.register(t)
+
+	plan, err := client.FirewallRule.SyncFirewallRules(ctx, "fw", syncDesiredFirewallRules)
+	if err != nil {
+		t.Fatalf("FirewallRule.SyncFirewallRules returned %+v", err)
+	}
+
+	if len(plan.Unchanged) != 3 || len(plan.Creates) != 2 || len(plan.Deletes) != 2 {
+		t.Fatalf("FirewallRule.SyncFirewallRules returned plan %s", plan)
+	}
+
+	expected := []string{
+		"create v6 udp 2001:db8::/32 53",
+		"create v4 icmp 0.0.0.0/0 ",
+		"delete 3",
+		"delete 5",
+	}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("FirewallRule.SyncFirewallRules made calls %v, expected %v", recorder.calls, expected)
+	}
+}
+
+func TestFireWallRuleServiceHandler_SyncFirewallRulesRollback(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := &firewallSyncRecorder{failOn: "create v4 icmp 0.0.0.0/0 "}
+	recorder.register(t)
+
+	if _, err := client.FirewallRule.SyncFirewallRules(ctx, "fw", syncDesiredFirewallRules); err == nil {
+		t.Fatal("FirewallRule.SyncFirewallRules expected an error")
+	}
+
+	expected := []string{
+		"create v6 udp 2001:db8::/32 53",
+		"create v4 icmp 0.0.0.0/0 ",
+		"delete 101",
+	}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		t.Errorf("FirewallRule.SyncFirewallRules made calls %v, expected %v", recorder.calls, expected)
+	}
+}
+
+func TestNormalizeFirewallPort(t *testing.T) {
+	tests := []struct {
+		protocol FirewallProtocol
+		port     string
+		expected string
+	}{
+		{FirewallProtocolTCP, "22", "22"},
+		{FirewallProtocolTCP, " 8000 - 9000 ", "8000:9000"},
+		{FirewallProtocolUDP, "53:53", "53"},
+		{FirewallProtocolTCP, "1:65535", ""},
+		{FirewallProtocolICMP, "22", ""},
+		{FirewallProtocolTCP, "bad", "bad"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeFirewallPort(tt.protocol, tt.port); got != tt.expected {
+			t.Errorf("normalizeFirewallPort(%s, %q) returned %q, expected %q", tt.protocol, tt.port, got, tt.expected)
+		}
+	}
+}
@@ -134,3 +134,29 @@ func TestFireWallRuleServiceHandler_Get(t *testing.T) {
 		t.Errorf("FirewallRule.Get returned %+v, expected %+v", firewallRule, expectedRule)
 	}
 }
+
+func TestFirewallRuleReq_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    FirewallRuleReq
+		fields []string
+	}{
+		{name: "valid v4", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "10.0.0.0", SubnetSize: 8, Port: "8000:9000"}},
+		{name: "valid v6", req: FirewallRuleReq{IPType: "v6", Protocol: "udp", Subnet: "2001:db8::", SubnetSize: 32, Port: "53"}},
+		{name: "cloudflare source", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Source: "cloudflare", Port: "443"}},
+		{name: "bad ip type", req: FirewallRuleReq{IPType: "v5", Protocol: "icmp", Subnet: "0.0.0.0"}, fields: []string{"ip_type", "subnet"}},
+		{name: "bad protocol", req: FirewallRuleReq{IPType: "v4", Protocol: "sctp", Subnet: "0.0.0.0"}, fields: []string{"protocol"}},
+		{name: "bad subnet", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "10.0.0.300", SubnetSize: 8}, fields: []string{"subnet"}},
+		{name: "mismatched family", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "::", SubnetSize: 0}, fields: []string{"subnet"}},
+		{name: "subnet size", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "10.0.0.0", SubnetSize: 33}, fields: []string{"subnet_size"}},
+		{name: "host bits", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "10.0.0.1", SubnetSize: 24}, fields: []string{"subnet"}},
+		{name: "port on icmp", req: FirewallRuleReq{IPType: "v4", Protocol: "icmp", Subnet: "0.0.0.0", Port: "22"}, fields: []string{"port"}},
+		{name: "bad port range", req: FirewallRuleReq{IPType: "v4", Protocol: "tcp", Subnet: "0.0.0.0", Port: "9000:8000"}, fields: []string{"port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.req.Validate(), tt.fields)
+		})
+	}
+}
@@ -81,6 +81,9 @@ type Client struct {
 
 	// Optional function called after every successful request made to the Vultr API
 	onRequestCompleted RequestCompletionCallback
+
+	// Validate request structs before sending them to the Vultr API
+	validateRequests bool
 }
 
 // RequestCompletionCallback defines the type of the request callback function
@@ -3,7 +3,11 @@ package govultr
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -77,38 +81,154 @@ type InstanceServiceHandler struct {
 
 // Instance represents a VPS
 type Instance struct {
-	ID               string   `json:"id"`
-	Os               string   `json:"os"`
-	RAM              int      `json:"ram"`
-	Disk             int      `json:"disk"`
-	Plan             string   `json:"plan"`
-	MainIP           string   `json:"main_ip"`
-	VPCOnly          bool     `json:"vpc_only"`
-	VCPUCount        int      `json:"vcpu_count"`
-	Region           string   `json:"region"`
-	DefaultPassword  string   `json:"default_password,omitempty"`
-	DateCreated      string   `json:"date_created"`
-	Status           string   `json:"status"`
-	AllowedBandwidth int      `json:"allowed_bandwidth"`
-	NetmaskV4        string   `json:"netmask_v4"`
-	GatewayV4        string   `json:"gateway_v4"`
-	PowerStatus      string   `json:"power_status"`
-	ServerStatus     string   `json:"server_status"`
-	V6Network        string   `json:"v6_network"`
-	V6MainIP         string   `json:"v6_main_ip"`
-	V6NetworkSize    int      `json:"v6_network_size"`
-	Label            string   `json:"label"`
-	InternalIP       string   `json:"internal_ip"`
-	KVM              string   `json:"kvm"`
-	OsID             int      `json:"os_id"`
-	AppID            int      `json:"app_id"`
-	ImageID          string   `json:"image_id"`
-	SnapshotID       string   `json:"snapshot_id"`
-	FirewallGroupID  string   `json:"firewall_group_id"`
-	Features         []string `json:"features"`
-	Hostname         string   `json:"hostname"`
-	Tags             []string `json:"tags"`
-	UserScheme       string   `json:"user_scheme"`
+	ID               string         `json:"id"`
+	Os               string         `json:"os"`
+	RAM              int            `json:"ram"`
+	Disk             int            `json:"disk"`
+	Plan             string         `json:"plan"`
+	MainIP           string         `json:"main_ip"`
+	VPCOnly          bool           `json:"vpc_only"`
+	VCPUCount        int            `json:"vcpu_count"`
+	Region           string         `json:"region"`
+	DefaultPassword  string         `json:"default_password,omitempty"`
+	DateCreated      string         `json:"date_created"`
+	Status           InstanceStatus `json:"status"`
+	AllowedBandwidth int            `json:"allowed_bandwidth"`
+	NetmaskV4        string         `json:"netmask_v4"`
+	GatewayV4        string         `json:"gateway_v4"`
+	PowerStatus      PowerStatus    `json:"power_status"`
+	ServerStatus     ServerStatus   `json:"server_status"`
+	V6Network        string         `json:"v6_network"`
+	V6MainIP         string         `json:"v6_main_ip"`
+	V6NetworkSize    int            `json:"v6_network_size"`
+	Label            string         `json:"label"`
+	InternalIP       string         `json:"internal_ip"`
+	KVM              string         `json:"kvm"`
+	OsID             int            `json:"os_id"`
+	AppID            int            `json:"app_id"`
+	ImageID          string         `json:"image_id"`
+	SnapshotID       string         `json:"snapshot_id"`
+	FirewallGroupID  string         `json:"firewall_group_id"`
+	Features         []string       `json:"features"`
+	Hostname         string         `json:"hostname"`
+	Tags             []string       `json:"tags"`
+	UserScheme       UserScheme     `json:"user_scheme"`
+}
+
+// CreatedAt parses DateCreated with ParseTimestamp
+func (i *Instance) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(i.DateCreated)
+}
+
+// InstanceStatus is the subscription status of an instance
+type InstanceStatus string
+
+// Instance statuses reported by the API
+const (
+	InstanceStatusActive    InstanceStatus = "active"
+	InstanceStatusPending   InstanceStatus = "pending"
+	InstanceStatusSuspended InstanceStatus = "suspended"
+	InstanceStatusResizing  InstanceStatus = "resizing"
+)
+
+// Valid reports whether the status is one known to this version of govultr
+func (s InstanceStatus) Valid() bool {
+	switch s {
+	case InstanceStatusActive, InstanceStatusPending, InstanceStatusSuspended, InstanceStatusResizing:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (s InstanceStatus) String() string {
+	return string(s)
+}
+
+// PowerStatus is the power state of an instance
+type PowerStatus string
+
+// Power statuses reported by the API
+const (
+	PowerStatusRunning PowerStatus = "running"
+	PowerStatusStopped PowerStatus = "stopped"
+)
+
+// Valid reports whether the power status is one known to this version of govultr
+func (s PowerStatus) Valid() bool {
+	switch s {
+	case PowerStatusRunning, PowerStatusStopped:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (s PowerStatus) String() string {
+	return string(s)
+}
+
+// ServerStatus is the health of the server backing an instance
+type ServerStatus string
+
+// Server statuses reported by the API
+const (
+	ServerStatusNone              ServerStatus = "none"
+	ServerStatusLocked            ServerStatus = "locked"
+	ServerStatusInstallingBooting ServerStatus = "installingbooting"
+	ServerStatusOK                ServerStatus = "ok"
+)
+
+// Valid reports whether the server status is one known to this version of govultr
+func (s ServerStatus) Valid() bool {
+	switch s {
+	case ServerStatusNone, ServerStatusLocked, ServerStatusInstallingBooting, ServerStatusOK:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (s ServerStatus) String() string {
+	return string(s)
+}
+
+// AutoBackups toggles automatic backups when creating or updating an instance
+type AutoBackups string
+
+// Automatic backup settings accepted by the API
+const (
+	AutoBackupsEnabled  AutoBackups = "enabled"
+	AutoBackupsDisabled AutoBackups = "disabled"
+)
+
+// Valid reports whether the setting is one known to this version of govultr
+func (a AutoBackups) Valid() bool {
+	return a == AutoBackupsEnabled || a == AutoBackupsDisabled
+}
+
+// String implements fmt.Stringer
+func (a AutoBackups) String() string {
+	return string(a)
+}
+
+// UserScheme is the login user configured on Linux instances
+type UserScheme string
+
+// User schemes accepted by the API
+const (
+	UserSchemeRoot    UserScheme = "root"
+	UserSchemeLimited UserScheme = "limited"
+)
+
+// Valid reports whether the user scheme is one known to this version of govultr
+func (u UserScheme) Valid() bool {
+	return u == UserSchemeRoot || u == UserSchemeLimited
+}
+
+// String implements fmt.Stringer
+func (u UserScheme) String() string {
+	return string(u)
 }
 
 type instanceBase struct {
@@ -271,12 +391,12 @@ type InstanceCreateReq struct {
 	VPCOnly           *bool    `json:"vpc_only,omitempty"`
 
 	SSHKeys         []string          `json:"sshkey_id,omitempty"`
-	Backups         string            `json:"backups,omitempty"`
+	Backups         AutoBackups       `json:"backups,omitempty"`
 	DDOSProtection  *bool             `json:"ddos_protection,omitempty"`
 	UserData        string            `json:"user_data,omitempty"`
 	ReservedIPv4    string            `json:"reserved_ipv4,omitempty"`
 	ActivationEmail *bool             `json:"activation_email,omitempty"`
-	UserScheme      string            `json:"user_scheme,omitempty"`
+	UserScheme      UserScheme        `json:"user_scheme,omitempty"`
 	AppVariables    map[string]string `json:"app_variables,omitempty"`
 
 	// Deprecated: VPC2 is no longer supported
@@ -288,23 +408,59 @@ type InstanceCreateReq struct {
 	BlockDevices []InstanceBlockDevice `json:"block_devices"`
 }
 
+// Validate checks the request for mistakes the API would otherwise reject
+func (i *InstanceCreateReq) Validate() error {
+	v := newValidation("InstanceCreateReq")
+	v.required("region", i.Region)
+	v.required("plan", i.Plan)
+
+	var sources []string
+	for name, set := range map[string]bool{
+		"os_id":       i.OsID != 0,
+		"iso_id":      i.ISOID != "",
+		"app_id":      i.AppID != 0,
+		"image_id":    i.ImageID != "",
+		"snapshot_id": i.SnapshotID != "",
+	} {
+		if set {
+			sources = append(sources, name)
+		}
+	}
+	if len(sources) > 1 {
+		sort.Strings(sources)
+		v.addf(sources[0], "cannot be combined with %s", strings.Join(sources[1:], ", "))
+	}
+
+	if i.Backups != "" && !i.Backups.Valid() {
+		v.addf("backups", "unknown value %q", i.Backups)
+	}
+	if i.UserScheme != "" && !i.UserScheme.Valid() {
+		v.addf("user_scheme", "unknown value %q", i.UserScheme)
+	}
+	if i.ReservedIPv4 != "" && net.ParseIP(i.ReservedIPv4).To4() == nil {
+		v.addf("reserved_ipv4", "%q is not an IPv4 address", i.ReservedIPv4)
+	}
+
+	return v.err()
+}
+
 // InstanceUpdateReq struct used to update an instance.
 type InstanceUpdateReq struct {
-	Plan            string   `json:"plan,omitempty"`
-	Label           string   `json:"label,omitempty"`
-	Tags            []string `json:"tags"`
-	OsID            int      `json:"os_id,omitempty"`
-	AppID           int      `json:"app_id,omitempty"`
-	ImageID         string   `json:"image_id,omitempty"`
-	EnableIPv6      *bool    `json:"enable_ipv6,omitempty"`
-	EnableVPC       *bool    `json:"enable_vpc,omitempty"`
-	AttachVPC       []string `json:"attach_vpc,omitempty"`
-	DetachVPC       []string `json:"detach_vpc,omitempty"`
-	Backups         string   `json:"backups,omitempty"`
-	DDOSProtection  *bool    `json:"ddos_protection"`
-	UserData        string   `json:"user_data,omitempty"`
-	FirewallGroupID string   `json:"firewall_group_id,omitempty"`
-	UserScheme      string   `json:"user_scheme,omitempty"`
+	Plan            string      `json:"plan,omitempty"`
+	Label           string      `json:"label,omitempty"`
+	Tags            []string    `json:"tags"`
+	OsID            int         `json:"os_id,omitempty"`
+	AppID           int         `json:"app_id,omitempty"`
+	ImageID         string      `json:"image_id,omitempty"`
+	EnableIPv6      *bool       `json:"enable_ipv6,omitempty"`
+	EnableVPC       *bool       `json:"enable_vpc,omitempty"`
+	AttachVPC       []string    `json:"attach_vpc,omitempty"`
+	DetachVPC       []string    `json:"detach_vpc,omitempty"`
+	Backups         AutoBackups `json:"backups,omitempty"`
+	DDOSProtection  *bool       `json:"ddos_protection"`
+	UserData        string      `json:"user_data,omitempty"`
+	FirewallGroupID string      `json:"firewall_group_id,omitempty"`
+	UserScheme      UserScheme  `json:"user_scheme,omitempty"`
 
 	// Deprecated: VPC2 is no longer supported
 	EnableVPC2 *bool `json:"enable_vpc2,omitempty"`
@@ -316,6 +472,21 @@ type InstanceUpdateReq struct {
 	DetachVPC2 []string `json:"detach_vpc2,omitempty"`
 }
 
+// Validate checks the request for mistakes the API would otherwise reject
+func (i *InstanceUpdateReq) Validate() error {
+	v := newValidation("InstanceUpdateReq")
+	if i.OsID != 0 && (i.AppID != 0 || i.ImageID != "") {
+		v.addf("os_id", "cannot be combined with app_id or image_id")
+	}
+	if i.Backups != "" && !i.Backups.Valid() {
+		v.addf("backups", "unknown value %q", i.Backups)
+	}
+	if i.UserScheme != "" && !i.UserScheme.Valid() {
+		v.addf("user_scheme", "unknown value %q", i.UserScheme)
+	}
+	return v.err()
+}
+
 // ReinstallReq struct used to allow changes during a reinstall
 type ReinstallReq struct {
 	Hostname string `json:"hostname,omitempty"`
@@ -343,6 +514,10 @@ type instanceRebootReq struct {
 
 // Create will create the server with the given parameters
 func (i *InstanceServiceHandler) Create(ctx context.Context, instanceReq *InstanceCreateReq) (*Instance, *http.Response, error) {
+	if err := i.client.validate(instanceReq); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := i.client.NewRequest(ctx, http.MethodPost, instancePath, instanceReq)
 	if err != nil {
 		return nil, nil, err
@@ -377,6 +552,10 @@ func (i *InstanceServiceHandler) Get(ctx context.Context, instanceID string) (*I
 
 // Update will update the server with the given parameters
 func (i *InstanceServiceHandler) Update(ctx context.Context, instanceID string, instanceReq *InstanceUpdateReq) (*Instance, *http.Response, error) { //nolint:lll
+	if err := i.client.validate(instanceReq); err != nil {
+		return nil, nil, err
+	}
+
 	uri := fmt.Sprintf("%s/%s", instancePath, instanceID)
 
 	req, err := i.client.NewRequest(ctx, http.MethodPatch, uri, instanceReq)
@@ -1,10 +1,12 @@
 package govultr
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -1131,3 +1133,55 @@ func TestServerServiceHandler_CreateMarketplace(t *testing.T) {
 		t.Errorf("Instance.Create returned %+v, expected %+v", server, expected)
 	}
 }
+
+func TestInstanceStatus(t *testing.T) {
+	var instance Instance
+	if err := json.Unmarshal([]byte(`{"status":"migrating","power_status":"running","server_status":"ok","user_scheme":"limited"}`), &instance); err != nil {
+		t.Fatalf("json.Unmarshal returned %+v", err)
+	}
+
+	if instance.Status != "migrating" || instance.Status.Valid() {
+		t.Errorf("unknown instance status %q should be preserved and not valid", instance.Status)
+	}
+
+	if instance.PowerStatus != PowerStatusRunning || !instance.PowerStatus.Valid() {
+		t.Errorf("power status returned %q, expected %q", instance.PowerStatus, PowerStatusRunning)
+	}
+
+	if instance.ServerStatus != ServerStatusOK || !instance.ServerStatus.Valid() {
+		t.Errorf("server status returned %q, expected %q", instance.ServerStatus, ServerStatusOK)
+	}
+
+	if instance.UserScheme != UserSchemeLimited || instance.UserScheme.String() != "limited" {
+		t.Errorf("user scheme returned %q, expected %q", instance.UserScheme, UserSchemeLimited)
+	}
+
+	body, err := json.Marshal(&InstanceUpdateReq{Backups: AutoBackupsEnabled})
+	if err != nil {
+		t.Fatalf("json.Marshal returned %+v", err)
+	}
+
+	if !strings.Contains(string(body), `"backups":"enabled"`) {
+		t.Errorf("json.Marshal returned %s, expected backups to be enabled", body)
+	}
+}
+
+func TestInstanceCreateReq_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    InstanceCreateReq
+		fields []string
+	}{
+		{name: "valid", req: InstanceCreateReq{Region: "ewr", Plan: "vc2-1c-2gb", OsID: 362, Backups: AutoBackupsEnabled}},
+		{name: "missing region and plan", req: InstanceCreateReq{OsID: 362}, fields: []string{"region", "plan"}},
+		{name: "os and snapshot", req: InstanceCreateReq{Region: "ewr", Plan: "vc2-1c-2gb", OsID: 362, SnapshotID: "abc"}, fields: []string{"os_id"}},
+		{name: "bad enums", req: InstanceCreateReq{Region: "ewr", Plan: "vc2-1c-2gb", Backups: "on", UserScheme: "admin"}, fields: []string{"backups", "user_scheme"}},
+		{name: "bad reserved ip", req: InstanceCreateReq{Region: "ewr", Plan: "vc2-1c-2gb", ReservedIPv4: "::1"}, fields: []string{"reserved_ipv4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.req.Validate(), tt.fields)
+		})
+	}
+}
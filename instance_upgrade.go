@@ -0,0 +1,161 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ErrNoPlanUpgrade is returned when no available plan upgrade satisfies the requested target
+var ErrNoPlanUpgrade = errors.New("no available plan upgrade meets the requested target")
+
+// PlanUpgradeTarget describes the minimum resources a plan upgrade must provide.
+// RAM is in MB and Disk is in GB, matching the Plan fields. Type optionally
+// restricts the candidates to a single plan type such as vc2 or vhf.
+type PlanUpgradeTarget struct {
+	VCPUCount int
+	RAM       int
+	Disk      int
+	Type      PlanType
+}
+
+func (p *PlanUpgradeTarget) satisfiedBy(plan *Plan) bool {
+	return plan.VCPUCount >= p.VCPUCount && plan.RAM >= p.RAM && plan.Disk >= p.Disk
+}
+
+// RecommendPlanUpgrade returns the cheapest plan that the instance can be upgraded to, which
+// is available in the instance's region and meets the CPU, RAM and disk target.
+func RecommendPlanUpgrade(ctx context.Context, client *Client, instanceID string, target *PlanUpgradeTarget) (*Plan, error) {
+	if target == nil {
+		target = &PlanUpgradeTarget{}
+	}
+
+	instance, _, err := client.Instance.Get(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrades, _, err := client.Instance.GetUpgrades(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability, _, err := client.Region.Availability(ctx, instance.Region, target.Type.String())
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := listAll(func(options *ListOptions) ([]Plan, *Meta, *http.Response, error) {
+		return client.Plan.List(ctx, target.Type, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cheapestPlanUpgrade(plans, upgrades.Plans, availability.AvailablePlans, instance.Plan, target)
+}
+
+func cheapestPlanUpgrade(plans []Plan, upgradable, available []string, current string, target *PlanUpgradeTarget) (*Plan, error) {
+	allowed := make(map[string]bool, len(upgradable))
+	for _, id := range upgradable {
+		allowed[id] = true
+	}
+
+	inRegion := make(map[string]bool, len(available))
+	for _, id := range available {
+		inRegion[id] = true
+	}
+
+	var candidates []Plan
+	for idx := range plans {
+		plan := plans[idx]
+		if plan.ID == current || !allowed[plan.ID] || !inRegion[plan.ID] || !target.satisfiedBy(&plan) {
+			continue
+		}
+		candidates = append(candidates, plan)
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNoPlanUpgrade
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].MonthlyCost != candidates[b].MonthlyCost {
+			return candidates[a].MonthlyCost < candidates[b].MonthlyCost
+		}
+		return candidates[a].ID < candidates[b].ID
+	})
+
+	return &candidates[0], nil
+}
+
+// UpgradeInstancePlan changes the plan of an instance and waits until the instance is active
+// again and reports the resources of the new plan. The plan must be one returned by GetUpgrades.
+func UpgradeInstancePlan(ctx context.Context, client *Client, instanceID, planID string, wait *WaitOptions) (*Instance, error) {
+	upgrades, _, err := client.Instance.GetUpgrades(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsString(upgrades.Plans, planID) {
+		return nil, fmt.Errorf("plan %s is not an available upgrade for instance %s", planID, instanceID)
+	}
+
+	plans, err := listAll(func(options *ListOptions) ([]Plan, *Meta, *http.Response, error) {
+		return client.Plan.List(ctx, "", options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *Plan
+	for idx := range plans {
+		if plans[idx].ID == planID {
+			plan = &plans[idx]
+			break
+		}
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("plan %s was not found", planID)
+	}
+
+	current, _, err := client.Instance.Get(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tags are always sent on update so the current set must be carried over
+	if _, _, err = client.Instance.Update(ctx, instanceID, &InstanceUpdateReq{Plan: planID, Tags: current.Tags}); err != nil {
+		return nil, err
+	}
+
+	var instance *Instance
+	err = waitFor(ctx, wait, fmt.Sprintf("instance %s to upgrade to %s", instanceID, planID), func(ctx context.Context) (bool, error) {
+		instance, _, err = client.Instance.Get(ctx, instanceID)
+		if err != nil {
+			return false, err
+		}
+
+		return instance.Status == InstanceStatusActive &&
+			instance.Plan == planID &&
+			instance.VCPUCount == plan.VCPUCount &&
+			instance.RAM == plan.RAM &&
+			instance.Disk == plan.Disk, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
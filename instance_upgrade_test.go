@@ -0,0 +1,117 @@
+package govultr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const upgradePlansResponse = `{"plans":[
+	{"id":"vc2-1c-2gb","vcpu_count":1,"ram":2048,"disk":55,"monthly_cost":10,"type":"vc2"},
+	{"id":"vc2-2c-4gb","vcpu_count":2,"ram":4096,"disk":80,"monthly_cost":20,"type":"vc2"},
+	{"id":"vc2-4c-8gb","vcpu_count":4,"ram":8192,"disk":160,"monthly_cost":40,"type":"vc2"},
+	{"id":"vhf-2c-4gb","vcpu_count":2,"ram":4096,"disk":128,"monthly_cost":24,"type":"vhf"},
+	{"id":"vc2-6c-16gb","vcpu_count":6,"ram":16384,"disk":320,"monthly_cost":80,"type":"vc2"}
+],"meta":{"total":5,"links":{"next":"","prev":""}}}`
+
+func setupInstanceUpgrade() {
+	mux.HandleFunc("/v2/instances/14b3e7d6-ffb5-4994-8502-57fcd9db3b33/upgrades", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"upgrades":{"plans":["vc2-2c-4gb","vc2-4c-8gb","vhf-2c-4gb","vc2-6c-16gb"]}}`)
+	})
+
+	mux.HandleFunc("/v2/regions/ewr/availability", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"available_plans":["vc2-1c-2gb","vc2-4c-8gb","vhf-2c-4gb","vc2-6c-16gb"]}`)
+	})
+
+	mux.HandleFunc("/v2/plans", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, upgradePlansResponse)
+	})
+}
+
+func TestRecommendPlanUpgrade(t *testing.T) {
+	setup()
+	defer teardown()
+	setupInstanceUpgrade()
+
+	mux.HandleFunc("/v2/instances/14b3e7d6-ffb5-4994-8502-57fcd9db3b33", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, defaultInstanceListResponse)
+	})
+
+	tests := []struct {
+		name     string
+		target   *PlanUpgradeTarget
+		expected string
+		err      error
+	}{
+		{name: "cheapest available", target: &PlanUpgradeTarget{VCPUCount: 2}, expected: "vhf-2c-4gb"},
+		{name: "ram target", target: &PlanUpgradeTarget{RAM: 8192}, expected: "vc2-4c-8gb"},
+		{name: "disk target", target: &PlanUpgradeTarget{Disk: 200}, expected: "vc2-6c-16gb"},
+		{name: "unsatisfiable", target: &PlanUpgradeTarget{VCPUCount: 32}, err: ErrNoPlanUpgrade},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := RecommendPlanUpgrade(ctx, client, "14b3e7d6-ffb5-4994-8502-57fcd9db3b33", tt.target)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("RecommendPlanUpgrade returned %+v, expected %+v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RecommendPlanUpgrade returned %+v", err)
+			}
+
+			if plan.ID != tt.expected {
+				t.Errorf("RecommendPlanUpgrade returned %s, expected %s", plan.ID, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpgradeInstancePlan(t *testing.T) {
+	setup()
+	defer teardown()
+	setupInstanceUpgrade()
+
+	var upgraded bool
+	gets := 0
+	mux.HandleFunc("/v2/instances/14b3e7d6-ffb5-4994-8502-57fcd9db3b33", func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodPatch:
+			upgraded = true
+			fmt.Fprint(writer, defaultInstanceListResponse)
+		case http.MethodGet:
+			gets++
+			switch {
+			case !upgraded:
+				fmt.Fprint(writer, defaultInstanceListResponse)
+			case gets < 4:
+				fmt.Fprint(writer, `{"instance":{"id":"14b3e7d6-ffb5-4994-8502-57fcd9db3b33","plan":"vc2-4c-8gb","status":"pending"}}`)
+			default:
+				fmt.Fprint(writer, `{"instance":{"id":"14b3e7d6-ffb5-4994-8502-57fcd9db3b33","plan":"vc2-4c-8gb","status":"active","vcpu_count":4,"ram":8192,"disk":160}}`)
+			}
+		}
+	})
+
+	wait := &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+	instance, err := UpgradeInstancePlan(ctx, client, "14b3e7d6-ffb5-4994-8502-57fcd9db3b33", "vc2-4c-8gb", wait)
+	if err != nil {
+		t.Fatalf("UpgradeInstancePlan returned %+v", err)
+	}
+
+	if !upgraded {
+		t.Errorf("UpgradeInstancePlan did not update the instance plan")
+	}
+
+	if instance.Plan != "vc2-4c-8gb" || instance.RAM != 8192 || instance.Status != "active" {
+		t.Errorf("UpgradeInstancePlan returned %+v", instance)
+	}
+
+	if _, err := UpgradeInstancePlan(ctx, client, "14b3e7d6-ffb5-4994-8502-57fcd9db3b33", "vc2-1c-2gb", wait); err == nil {
+		t.Errorf("UpgradeInstancePlan expected an error for a plan that is not an available upgrade")
+	}
+}
@@ -0,0 +1,391 @@
+package govultr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	kubeconfigAPIVersion = "v1"
+	kubeconfigKind       = "Config"
+	kubeconfigFileMode   = 0o600
+	kubeconfigDirMode    = 0o700
+	kubeconfigIndent     = 2
+	kubeExecAPIVersion   = "client.authentication.k8s.io/v1beta1"
+	kubeOIDCLoginCommand = "kubectl"
+)
+
+// KubeconfigFile is the structured form of a kubeconfig
+type KubeconfigFile struct {
+	APIVersion     string                   `json:"apiVersion"`
+	Kind           string                   `json:"kind"`
+	Clusters       []KubeconfigNamedCluster `json:"clusters"`
+	Users          []KubeconfigNamedUser    `json:"users"`
+	Contexts       []KubeconfigNamedContext `json:"contexts"`
+	CurrentContext string                   `json:"current-context,omitempty"`
+}
+
+// KubeconfigNamedCluster is a cluster entry in a kubeconfig
+type KubeconfigNamedCluster struct {
+	Name    string            `json:"name"`
+	Cluster KubeconfigCluster `json:"cluster"`
+}
+
+// KubeconfigCluster holds the address and CA of a cluster's API server
+type KubeconfigCluster struct {
+	Server                   string `json:"server"`
+	CertificateAuthorityData string `json:"certificate-authority-data,omitempty"`
+	InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify,omitempty"`
+}
+
+// KubeconfigNamedUser is a user entry in a kubeconfig
+type KubeconfigNamedUser struct {
+	Name string         `json:"name"`
+	User KubeconfigUser `json:"user"`
+}
+
+// KubeconfigUser holds the credentials used to authenticate to a cluster
+type KubeconfigUser struct {
+	ClientCertificateData string          `json:"client-certificate-data,omitempty"`
+	ClientKeyData         string          `json:"client-key-data,omitempty"`
+	Token                 string          `json:"token,omitempty"`
+	Exec                  *KubeconfigExec `json:"exec,omitempty"`
+}
+
+// KubeconfigExec configures an exec credential plugin
+type KubeconfigExec struct {
+	APIVersion      string   `json:"apiVersion"`
+	Command         string   `json:"command"`
+	Args            []string `json:"args,omitempty"`
+	InteractiveMode string   `json:"interactiveMode,omitempty"`
+}
+
+// KubeconfigNamedContext is a context entry in a kubeconfig
+type KubeconfigNamedContext struct {
+	Name    string            `json:"name"`
+	Context KubeconfigContext `json:"context"`
+}
+
+// KubeconfigContext pairs a cluster with the user to access it as
+type KubeconfigContext struct {
+	Cluster   string `json:"cluster"`
+	User      string `json:"user"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Decode decodes the base64 kubeconfig returned by GetKubeConfig
+func (k *KubeConfig) Decode() (*KubeconfigFile, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(k.KubeConfig))
+	if err != nil {
+		return nil, fmt.Errorf("decoding kubeconfig: %w", err)
+	}
+	return ParseKubeconfig(data)
+}
+
+// ParseKubeconfig parses a kubeconfig document
+func ParseKubeconfig(data []byte) (*KubeconfigFile, error) {
+	config := &KubeconfigFile{}
+	if err := unmarshalYAML(data, config); err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	return config, nil
+}
+
+// YAML returns the kubeconfig as a YAML document
+func (f *KubeconfigFile) YAML() ([]byte, error) {
+	return marshalYAML(f)
+}
+
+// KubeconfigName returns the name used for a cluster's kubeconfig entries,
+// derived from its label and falling back to its ID
+func KubeconfigName(cluster *Cluster) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(strings.TrimSpace(cluster.Label)) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.', c == '_':
+			b.WriteRune(c)
+		default:
+			b.WriteByte('-')
+		}
+	}
+
+	name := strings.Trim(b.String(), "-")
+	if name == "" {
+		name = cluster.ID
+	}
+	return "vke-" + name
+}
+
+// RenameForCluster renames the cluster, user and context entries after the
+// cluster's label so kubeconfigs of several clusters can live side by side
+func (f *KubeconfigFile) RenameForCluster(cluster *Cluster) {
+	name := KubeconfigName(cluster)
+	user := name + "-admin"
+
+	clusters := map[string]string{}
+	for idx := range f.Clusters {
+		clusters[f.Clusters[idx].Name] = name
+		f.Clusters[idx].Name = name
+	}
+
+	users := map[string]string{}
+	for idx := range f.Users {
+		users[f.Users[idx].Name] = user
+		f.Users[idx].Name = user
+	}
+
+	for idx := range f.Contexts {
+		c := &f.Contexts[idx]
+		if f.CurrentContext == c.Name {
+			f.CurrentContext = name
+		}
+		c.Name = name
+		if renamed, ok := clusters[c.Context.Cluster]; ok {
+			c.Context.Cluster = renamed
+		}
+		if renamed, ok := users[c.Context.User]; ok {
+			c.Context.User = renamed
+		}
+	}
+}
+
+// AddOIDCUser adds a user that logs in through the cluster's OIDC provider with
+// the kubelogin plugin (kubectl oidc-login) and a context using it. The context
+// is named after the cluster with an "-oidc" suffix.
+func (f *KubeconfigFile) AddOIDCUser(cluster *Cluster) error {
+	oidc := cluster.OIDCConfig
+	if oidc.IssuerURL == "" || oidc.ClientID == "" {
+		return errors.New("cluster has no OIDC configuration")
+	}
+	if len(f.Clusters) == 0 {
+		return errors.New("kubeconfig has no cluster entry")
+	}
+
+	name := KubeconfigName(cluster) + "-oidc"
+	f.Users = append(f.Users, KubeconfigNamedUser{
+		Name: name,
+		User: KubeconfigUser{
+			Exec: &KubeconfigExec{
+				APIVersion: kubeExecAPIVersion,
+				Command:    kubeOIDCLoginCommand,
+				Args: []string{
+					"oidc-login",
+					"get-token",
+					"--oidc-issuer-url=" + oidc.IssuerURL,
+					"--oidc-client-id=" + oidc.ClientID,
+				},
+				InteractiveMode: "IfAvailable",
+			},
+		},
+	})
+	f.Contexts = append(f.Contexts, KubeconfigNamedContext{
+		Name:    name,
+		Context: KubeconfigContext{Cluster: f.Clusters[0].Name, User: name},
+	})
+
+	return nil
+}
+
+// GetClusterKubeconfig fetches and decodes a cluster's kubeconfig with entries
+// named after the cluster's label, adding an OIDC user when the cluster has OIDC configured
+func (k *KubernetesHandler) GetClusterKubeconfig(ctx context.Context, vkeID string) (*KubeconfigFile, error) {
+	cluster, _, err := k.GetCluster(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, _, err := k.GetKubeConfig(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := raw.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	config.RenameForCluster(cluster)
+	if cluster.OIDCConfig.IssuerURL != "" && cluster.OIDCConfig.ClientID != "" {
+		if err := config.AddOIDCUser(cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// MergeKubeconfig merges the entries of add into the kubeconfig document
+// existing and returns the result. Entries with the same name are replaced and
+// everything else in existing, including fields govultr does not model and comments,
+// is kept. The current context is switched to add's when setCurrent is true or
+// existing has none.
+func MergeKubeconfig(existing []byte, add *KubeconfigFile, setCurrent bool) ([]byte, error) {
+	base := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{base}}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		parsed := &yaml.Node{}
+		if err := yaml.Unmarshal(existing, parsed); err != nil {
+			return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+		}
+		if len(parsed.Content) > 0 {
+			if parsed.Content[0].Kind != yaml.MappingNode {
+				return nil, errors.New("parsing kubeconfig: document is not a mapping")
+			}
+			doc, base = parsed, parsed.Content[0]
+		}
+	}
+
+	addTree, err := kubeconfigNode(add)
+	if err != nil {
+		return nil, err
+	}
+
+	if mappingValue(base, "apiVersion") == nil {
+		setMappingValue(base, "apiVersion", kubeconfigAPIVersion)
+	}
+	if mappingValue(base, "kind") == nil {
+		setMappingValue(base, "kind", kubeconfigKind)
+	}
+
+	for _, key := range []string{"clusters", "users", "contexts"} {
+		current := mappingValue(base, key)
+		if current == nil || current.Kind != yaml.SequenceNode {
+			current = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			setMappingNode(base, key, current)
+		}
+		if entries := mappingValue(addTree, key); entries != nil {
+			mergeNamedEntries(current, entries)
+		}
+	}
+
+	currentContext := mappingValue(base, "current-context")
+	if add.CurrentContext != "" && (setCurrent || currentContext == nil || currentContext.Value == "") {
+		setMappingValue(base, "current-context", add.CurrentContext)
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(kubeconfigIndent)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MergeKubeconfigFile merges add into the kubeconfig at path, creating it if needed.
+// When path is a symlink the file it points to is updated.
+func MergeKubeconfigFile(path string, add *KubeconfigFile, setCurrent bool) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	existing, err := os.ReadFile(path) //nolint:gosec
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	merged, err := MergeKubeconfig(existing, add, setCurrent)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), kubeconfigDirMode); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a failure never leaves a truncated kubeconfig
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kubeconfig-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(merged); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(kubeconfigFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// kubeconfigNode converts a kubeconfig into a YAML mapping node
+func kubeconfigNode(config *KubeconfigFile) (*yaml.Node, error) {
+	doc, err := jsonNode(config)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content[0], nil
+}
+
+// mergeNamedEntries replaces entries of current with the same name as an entry in add and appends the rest
+func mergeNamedEntries(current, add *yaml.Node) {
+	for _, entry := range add.Content {
+		name := namedEntryName(entry)
+
+		replaced := false
+		for idx, existing := range current.Content {
+			if name != "" && namedEntryName(existing) == name {
+				current.Content[idx] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			current.Content = append(current.Content, entry)
+		}
+	}
+}
+
+func namedEntryName(entry *yaml.Node) string {
+	if name := mappingValue(entry, "name"); name != nil && name.Kind == yaml.ScalarNode {
+		return name.Value
+	}
+	return ""
+}
+
+// mappingValue returns the value stored under key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			return node.Content[idx+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key, value string) {
+	setMappingNode(node, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+func setMappingNode(node *yaml.Node, key string, value *yaml.Node) {
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			node.Content[idx+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
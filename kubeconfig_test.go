@@ -0,0 +1,330 @@
+package govultr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const testKubeconfig = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Q0EK
+    server: https://abc.vultr-k8s.com:6443
+  name: vke-abc
+contexts:
+- context:
+    cluster: vke-abc
+    user: admin
+  name: vke-abc
+current-context: vke-abc
+kind: Config
+users:
+- name: admin
+  user:
+    client-certificate-data: Q0VSVAo=
+    client-key-data: S0VZCg==
+`
+
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+preferences: {}
+clusters:
+  - name: other
+    cluster:
+      server: https://other.example.com
+      extensions:
+        - name: client.authentication.k8s.io/exec
+          extension:
+            audience: other
+  - name: vke-production
+    cluster:
+      server: https://stale.vultr-k8s.com:6443
+contexts:
+  - name: other
+    context:
+      cluster: other
+      user: other
+      namespace: apps
+current-context: other
+users:
+  - name: other
+    user:
+      auth-provider:
+        name: oidc
+        config:
+          idp-issuer-url: https://issuer.example.com
+`
+
+func TestKubeConfig_Decode(t *testing.T) {
+	raw := &KubeConfig{KubeConfig: base64.StdEncoding.EncodeToString([]byte(testKubeconfig))}
+
+	config, err := raw.Decode()
+	if err != nil {
+		t.Fatalf("KubeConfig.Decode returned %+v", err)
+	}
+
+	expected := &KubeconfigFile{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []KubeconfigNamedCluster{
+			{Name: "vke-abc", Cluster: KubeconfigCluster{Server: "https://abc.vultr-k8s.com:6443", CertificateAuthorityData: "Q0EK"}},
+		},
+		Users: []KubeconfigNamedUser{
+			{Name: "admin", User: KubeconfigUser{ClientCertificateData: "Q0VSVAo=", ClientKeyData: "S0VZCg=="}},
+		},
+		Contexts: []KubeconfigNamedContext{
+			{Name: "vke-abc", Context: KubeconfigContext{Cluster: "vke-abc", User: "admin"}},
+		},
+		CurrentContext: "vke-abc",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("KubeConfig.Decode returned %+v, expected %+v", config, expected)
+	}
+
+	if _, err := (&KubeConfig{KubeConfig: "not base64!"}).Decode(); err == nil {
+		t.Error("KubeConfig.Decode expected an error for invalid base64")
+	}
+}
+
+func TestKubeconfigFile_RenameForCluster(t *testing.T) {
+	config, err := ParseKubeconfig([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+
+	cluster := &Cluster{ID: "abc", Label: "Production EU", OIDCConfig: ClusterOIDCConfig{IssuerURL: "https://issuer", ClientID: "vke"}}
+	config.RenameForCluster(cluster)
+	if err := config.AddOIDCUser(cluster); err != nil {
+		t.Fatalf("KubeconfigFile.AddOIDCUser returned %+v", err)
+	}
+
+	if config.Clusters[0].Name != "vke-production-eu" || config.Users[0].Name != "vke-production-eu-admin" {
+		t.Errorf("KubeconfigFile.RenameForCluster returned %+v", config)
+	}
+
+	expectedContexts := []KubeconfigNamedContext{
+		{Name: "vke-production-eu", Context: KubeconfigContext{Cluster: "vke-production-eu", User: "vke-production-eu-admin"}},
+		{Name: "vke-production-eu-oidc", Context: KubeconfigContext{Cluster: "vke-production-eu", User: "vke-production-eu-oidc"}},
+	}
+	if !reflect.DeepEqual(config.Contexts, expectedContexts) || config.CurrentContext != "vke-production-eu" {
+		t.Errorf("KubeconfigFile contexts = %+v, current %q", config.Contexts, config.CurrentContext)
+	}
+
+	exec := config.Users[1].User.Exec
+	if exec == nil || !reflect.DeepEqual(exec.Args[2:], []string{"--oidc-issuer-url=https://issuer", "--oidc-client-id=vke"}) {
+		t.Errorf("KubeconfigFile.AddOIDCUser returned user %+v", config.Users[1])
+	}
+
+	if err := config.AddOIDCUser(&Cluster{Label: "plain"}); err == nil {
+		t.Error("KubeconfigFile.AddOIDCUser expected an error without OIDC configuration")
+	}
+
+	if name := KubeconfigName(&Cluster{ID: "abc", Label: " ** "}); name != "vke-abc" {
+		t.Errorf("KubeconfigName returned %q, expected vke-abc", name)
+	}
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	config, err := ParseKubeconfig([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+	config.RenameForCluster(&Cluster{Label: "production"})
+
+	merged, err := MergeKubeconfig([]byte(existingKubeconfig), config, false)
+	if err != nil {
+		t.Fatalf("MergeKubeconfig returned %+v", err)
+	}
+
+	result, err := ParseKubeconfig(merged)
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v for merged output:\n%s", err, merged)
+	}
+
+	var clusters []string
+	for _, c := range result.Clusters {
+		clusters = append(clusters, c.Name+" "+c.Cluster.Server)
+	}
+	expected := []string{"other https://other.example.com", "vke-production https://abc.vultr-k8s.com:6443"}
+	if !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("MergeKubeconfig clusters = %v, expected %v", clusters, expected)
+	}
+
+	if len(result.Users) != 2 || len(result.Contexts) != 2 || result.CurrentContext != "other" {
+		t.Errorf("MergeKubeconfig returned %+v", result)
+	}
+
+	// fields govultr does not model survive the merge
+	for _, kept := range []string{"preferences: {}", "audience: other", "idp-issuer-url: https://issuer.example.com", "namespace: apps"} {
+		if !strings.Contains(string(merged), kept) {
+			t.Errorf("MergeKubeconfig dropped %q:\n%s", kept, merged)
+		}
+	}
+
+	switched, err := MergeKubeconfig([]byte(existingKubeconfig), config, true)
+	if err != nil {
+		t.Fatalf("MergeKubeconfig returned %+v", err)
+	}
+	if !strings.Contains(string(switched), "current-context: vke-production\n") {
+		t.Errorf("MergeKubeconfig did not switch the current context:\n%s", switched)
+	}
+}
+
+// written by other tools, with comments, flow mappings, block scalars and quoting
+const foreignKubeconfig = `# managed by gcloud
+apiVersion: v1
+kind: Config
+clusters:
+- cluster: {server: 'https://gke.example.com', certificate-authority-data: "Q0EK"}
+  name: gke
+users:
+- name: gke
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: gke-gcloud-auth-plugin
+      installHint: |
+        Install gke-gcloud-auth-plugin for use with kubectl by following
+        https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl
+      provideClusterInfo: true
+contexts:
+- context: {cluster: gke, user: gke}
+  name: gke
+current-context: gke
+`
+
+func TestMergeKubeconfig_Foreign(t *testing.T) {
+	config, err := ParseKubeconfig([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+
+	merged, err := MergeKubeconfig([]byte(foreignKubeconfig), config, false)
+	if err != nil {
+		t.Fatalf("MergeKubeconfig returned %+v", err)
+	}
+
+	var before, after map[string]interface{}
+	if err := yaml.Unmarshal([]byte(foreignKubeconfig), &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(merged, &after); err != nil {
+		t.Fatalf("merged kubeconfig does not parse: %v\n%s", err, merged)
+	}
+
+	// the existing entries are unchanged and the new ones appended
+	for _, key := range []string{"clusters", "users", "contexts"} {
+		existing := before[key].([]interface{})
+		result := after[key].([]interface{})
+		if len(result) != len(existing)+1 || !reflect.DeepEqual(result[:len(existing)], existing) {
+			t.Errorf("MergeKubeconfig %s = %v, expected %v followed by the new entry", key, result, existing)
+		}
+	}
+	if after["current-context"] != "gke" {
+		t.Errorf("MergeKubeconfig current-context = %v, expected gke", after["current-context"])
+	}
+	if !strings.Contains(string(merged), "# managed by gcloud") {
+		t.Errorf("MergeKubeconfig dropped the comment:\n%s", merged)
+	}
+}
+
+func TestMergeKubeconfigFile(t *testing.T) {
+	config, err := ParseKubeconfig([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), ".kube", "config")
+	if err := MergeKubeconfigFile(path, config, false); err != nil {
+		t.Fatalf("MergeKubeconfigFile returned %+v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("MergeKubeconfigFile created the file with mode %v", info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := ParseKubeconfig(data)
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+	if !reflect.DeepEqual(written, config) {
+		t.Errorf("MergeKubeconfigFile wrote %+v, expected %+v", written, config)
+	}
+}
+
+func TestMergeKubeconfigFile_Symlink(t *testing.T) {
+	config, err := ParseKubeconfig([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("ParseKubeconfig returned %+v", err)
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "kubeconfig")
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte(existingKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	if err := MergeKubeconfigFile(link, config, false); err != nil {
+		t.Fatalf("MergeKubeconfigFile returned %+v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("MergeKubeconfigFile replaced the symlink with a %v file", info.Mode())
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "vke-abc") {
+		t.Errorf("MergeKubeconfigFile did not update the symlink target:\n%s", data)
+	}
+}
+
+func TestKubernetesHandler_GetClusterKubeconfig(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/abc", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"abc","label":"staging","oidc":{"issuer_url":"https://issuer","client_id":"vke"}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/config", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprintf(writer, `{"kube_config":%q}`, base64.StdEncoding.EncodeToString([]byte(testKubeconfig)))
+	})
+
+	config, err := client.Kubernetes.GetClusterKubeconfig(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Kubernetes.GetClusterKubeconfig returned %+v", err)
+	}
+
+	if config.CurrentContext != "vke-staging" || len(config.Contexts) != 2 || config.Contexts[1].Name != "vke-staging-oidc" {
+		t.Errorf("Kubernetes.GetClusterKubeconfig returned %+v", config)
+	}
+}
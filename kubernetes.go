@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -19,30 +20,40 @@ type KubernetesService interface {
 	UpdateCluster(ctx context.Context, vkeID string, updateReq *ClusterReqUpdate) error
 	DeleteCluster(ctx context.Context, id string) error
 	DeleteClusterWithResources(ctx context.Context, id string) error
+	PreviewClusterDeletion(ctx context.Context, vkeID string) (*ClusterDeletionInventory, error)
+	DeleteClusterConfirmed(ctx context.Context, vkeID, token string) (*ClusterDeletionInventory, error)
+	ExportClusterSpec(ctx context.Context, vkeID string) (*ClusterSpec, error)
+	CloneCluster(ctx context.Context, spec *ClusterSpec, opts *ClusterCloneOptions) (*Cluster, error)
 
 	CreateNodePool(ctx context.Context, vkeID string, nodePoolReq *NodePoolReq) (*NodePool, *http.Response, error)
 	ListNodePools(ctx context.Context, vkeID string, options *ListOptions) ([]NodePool, *Meta, *http.Response, error)
 	GetNodePool(ctx context.Context, vkeID, nodePoolID string) (*NodePool, *http.Response, error)
 	UpdateNodePool(ctx context.Context, vkeID, nodePoolID string, updateReq *NodePoolReqUpdate) (*NodePool, *http.Response, error)
 	DeleteNodePool(ctx context.Context, vkeID, nodePoolID string) error
+	ReplaceNodePool(ctx context.Context, vkeID, nodePoolID string, opts *NodePoolReplaceOptions) (*NodePool, error)
 
 	ListNodePoolLabels(ctx context.Context, vkeID, nodePoolID string) ([]NodePoolLabel, *http.Response, error)
 	CreateNodePoolLabel(ctx context.Context, vkeID string, nodePoolID string, nodePoolLabelReq *NodePoolLabelReq) (*NodePoolLabel, *http.Response, error) //nolint:lll
 	GetNodePoolLabel(ctx context.Context, vkeID string, nodePoolID string, nodePoolLabelID string) (*NodePoolLabel, *http.Response, error)
 	DeleteNodePoolLabel(ctx context.Context, vkeID string, nodePoolID string, nodePoolLabelID string) error
+	SyncNodePoolLabels(ctx context.Context, vkeID, nodePoolID string, desired map[string]string) (*NodePoolLabelSyncPlan, error)
 
 	ListNodePoolTaints(ctx context.Context, vkeID, nodePoolID string) ([]NodePoolTaint, *http.Response, error)
 	CreateNodePoolTaint(ctx context.Context, vkeID string, nodePoolID string, nodePoolTaintReq *NodePoolTaintReq) (*NodePoolTaint, *http.Response, error) //nolint:lll
 	GetNodePoolTaint(ctx context.Context, vkeID string, nodePoolID string, nodePoolTaintID string) (*NodePoolTaint, *http.Response, error)
 	DeleteNodePoolTaint(ctx context.Context, vkeID string, nodePoolID string, nodePoolTaintID string) error
+	SyncNodePoolTaints(ctx context.Context, vkeID, nodePoolID string, desired []Taint) (*NodePoolTaintSyncPlan, error)
 
 	ListWorkerNodes(ctx context.Context, vkeID, nodePoolID string, options *ListOptions) ([]Node, *Meta, *http.Response, error)
 	DeleteNodePoolInstance(ctx context.Context, vkeID, nodePoolID, nodeID string) error
 	RecycleNodePoolInstance(ctx context.Context, vkeID, nodePoolID, nodeID string) error
 
 	GetKubeConfig(ctx context.Context, vkeID string) (*KubeConfig, *http.Response, error)
+	GetClusterKubeconfig(ctx context.Context, vkeID string) (*KubeconfigFile, error)
 	GetVersions(ctx context.Context) (*Versions, *http.Response, error)
 
+	GetClusterHealth(ctx context.Context, vkeID string) (*ClusterHealth, error)
+
 	GetUpgrades(ctx context.Context, vkeID string) ([]string, *http.Response, error)
 	Upgrade(ctx context.Context, vkeID string, body *ClusterUpgradeReq) error
 }
@@ -63,13 +74,18 @@ type Cluster struct {
 	Endpoint        string            `json:"endpoint"`
 	Version         string            `json:"version"`
 	Region          string            `json:"region"`
-	Status          string            `json:"status"`
+	Status          VKEStatus         `json:"status"` // open-ended, see VKEStatus
 	HAControlPlanes bool              `json:"ha_controlplanes"`
 	FirewallGroupID string            `json:"firewall_group_id"`
 	OIDCConfig      ClusterOIDCConfig `json:"oidc"`
 	NodePools       []NodePool        `json:"node_pools"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (c *Cluster) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(c.DateCreated)
+}
+
 // NodePool represents a pool of nodes that are grouped by their label and plan type
 type NodePool struct {
 	ID           string            `json:"id"`
@@ -77,7 +93,7 @@ type NodePool struct {
 	DateUpdated  string            `json:"date_updated"`
 	Label        string            `json:"label"`
 	Plan         string            `json:"plan"`
-	Status       string            `json:"status"`
+	Status       VKEStatus         `json:"status"` // open-ended, see VKEStatus
 	NodeQuantity int               `json:"node_quantity"`
 	MinNodes     int               `json:"min_nodes"`
 	MaxNodes     int               `json:"max_nodes"`
@@ -90,13 +106,54 @@ type NodePool struct {
 	Nodes        []Node            `json:"nodes"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (n *NodePool) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(n.DateCreated)
+}
+
+// UpdatedAt parses DateUpdated with ParseTimestamp
+func (n *NodePool) UpdatedAt() (time.Time, error) {
+	return ParseTimestamp(n.DateUpdated)
+}
+
 // Node represents a node that will live within a nodepool
 type Node struct {
-	ID          string `json:"id"`
-	DateCreated string `json:"date_created"`
-	Label       string `json:"label"`
-	IP          string `json:"ip,omitempty"` // Optional, may not be present in older API responses
-	Status      string `json:"status"`
+	ID          string    `json:"id"`
+	DateCreated string    `json:"date_created"`
+	Label       string    `json:"label"`
+	IP          string    `json:"ip,omitempty"` // Optional, may not be present in older API responses
+	Status      VKEStatus `json:"status"`       // open-ended, see VKEStatus
+}
+
+// CreatedAt parses DateCreated with ParseTimestamp
+func (n *Node) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(n.DateCreated)
+}
+
+// VKEStatus is the status of a VKE cluster, node pool or node. The set of values is
+// open-ended: only active and pending are known, and any other value the API reports,
+// for example while a resource is being upgraded or deleted, is kept as is with Valid
+// returning false. Compare against VKEStatusActive rather than switching exhaustively.
+type VKEStatus string
+
+// Known VKE statuses; the API may report others
+const (
+	VKEStatusActive  VKEStatus = "active"
+	VKEStatusPending VKEStatus = "pending"
+)
+
+// Valid reports whether the status is one known to this version of govultr
+func (s VKEStatus) Valid() bool {
+	switch s {
+	case VKEStatusActive, VKEStatusPending:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (s VKEStatus) String() string {
+	return string(s)
 }
 
 // KubeConfig will contain the kubeconfig b64 encoded
@@ -137,6 +194,65 @@ type NodePoolReq struct {
 	UserData     string            `json:"user_data"`
 }
 
+// Validate checks the request for mistakes the API would otherwise reject
+func (c *ClusterReq) Validate() error {
+	v := newValidation("ClusterReq")
+	v.required("region", c.Region)
+	v.required("version", c.Version)
+
+	if len(c.NodePools) == 0 {
+		v.addf("node_pools", "at least one node pool is required")
+	}
+	for idx := range c.NodePools {
+		v.merge(fmt.Sprintf("node_pools[%d]", idx), c.NodePools[idx].Validate())
+	}
+
+	return v.err()
+}
+
+// Validate checks the request for mistakes the API would otherwise reject
+func (n *NodePoolReq) Validate() error {
+	v := newValidation("NodePoolReq")
+	v.required("label", n.Label)
+	v.required("plan", n.Plan)
+
+	if n.NodeQuantity < 1 {
+		v.addf("node_quantity", "must be at least 1")
+	}
+
+	validateNodePoolBounds(v, n.NodeQuantity, n.MinNodes, n.MaxNodes, n.AutoScaler != nil && *n.AutoScaler)
+	validateNodePoolLabels(v, n.Labels)
+	validateNodePoolTaints(v, n.Taints)
+
+	return v.err()
+}
+
+func validateNodePoolBounds(v *validation, quantity, minNodes, maxNodes int, autoScaler bool) {
+	if minNodes < 0 {
+		v.addf("min_nodes", "cannot be negative")
+	}
+	if maxNodes < 0 {
+		v.addf("max_nodes", "cannot be negative")
+	}
+	if maxNodes > 0 && minNodes > maxNodes {
+		v.addf("min_nodes", "%d is greater than max_nodes %d", minNodes, maxNodes)
+	}
+
+	if !autoScaler {
+		return
+	}
+
+	if minNodes < 1 {
+		v.addf("min_nodes", "must be at least 1 when the auto scaler is enabled")
+	}
+	if maxNodes < 1 {
+		v.addf("max_nodes", "must be at least 1 when the auto scaler is enabled")
+	}
+	if quantity > 0 && minNodes > 0 && maxNodes >= minNodes && (quantity < minNodes || quantity > maxNodes) {
+		v.addf("node_quantity", "%d is outside the auto scaler bounds %d-%d", quantity, minNodes, maxNodes)
+	}
+}
+
 // NodePoolReqUpdate struct used to update a node pool
 type NodePoolReqUpdate struct {
 	NodeQuantity int     `json:"node_quantity,omitempty"`
@@ -154,6 +270,18 @@ type NodePoolReqUpdate struct {
 	UserData *string `json:"user_data,omitempty"`
 }
 
+// Validate checks the request for mistakes the API would otherwise reject
+func (n *NodePoolReqUpdate) Validate() error {
+	v := newValidation("NodePoolReqUpdate")
+	if n.NodeQuantity < 0 {
+		v.addf("node_quantity", "cannot be negative")
+	}
+	validateNodePoolBounds(v, n.NodeQuantity, n.MinNodes, n.MaxNodes, n.AutoScaler != nil && *n.AutoScaler)
+	validateNodePoolLabels(v, n.Labels)
+	validateNodePoolTaints(v, n.Taints)
+	return v.err()
+}
+
 // NodePoolLabel struct used to define a NodePool Label
 type NodePoolLabel struct {
 	ID    string `json:"id"`
@@ -253,6 +381,10 @@ type ClusterUpgradeReq struct {
 
 // CreateCluster will create a Kubernetes cluster.
 func (k *KubernetesHandler) CreateCluster(ctx context.Context, createReq *ClusterReq) (*Cluster, *http.Response, error) {
+	if err := k.client.validate(createReq); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := k.client.NewRequest(ctx, http.MethodPost, vkePath, createReq)
 	if err != nil {
 		return nil, nil, err
@@ -339,6 +471,10 @@ func (k *KubernetesHandler) DeleteClusterWithResources(ctx context.Context, id s
 
 // CreateNodePool creates a nodepool on a VKE cluster
 func (k *KubernetesHandler) CreateNodePool(ctx context.Context, vkeID string, nodePoolReq *NodePoolReq) (*NodePool, *http.Response, error) {
+	if err := k.client.validate(nodePoolReq); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := k.client.NewRequest(ctx, http.MethodPost, fmt.Sprintf("%s/%s/node-pools", vkePath, vkeID), nodePoolReq)
 	if err != nil {
 		return nil, nil, err
@@ -394,6 +530,10 @@ func (k *KubernetesHandler) GetNodePool(ctx context.Context, vkeID, nodePoolID s
 
 // UpdateNodePool will allow you change the quantity of nodes within a nodepool
 func (k *KubernetesHandler) UpdateNodePool(ctx context.Context, vkeID, nodePoolID string, updateReq *NodePoolReqUpdate) (*NodePool, *http.Response, error) { //nolint:lll
+	if err := k.client.validate(updateReq); err != nil {
+		return nil, nil, err
+	}
+
 	req, err := k.client.NewRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/%s/node-pools/%s", vkePath, vkeID, nodePoolID), updateReq)
 	if err != nil {
 		return nil, nil, err
@@ -439,6 +579,10 @@ func (k *KubernetesHandler) ListNodePoolLabels(ctx context.Context, vkeID, nodeP
 
 // CreateNodePoolLabel creates a label on a node pool
 func (k *KubernetesHandler) CreateNodePoolLabel(ctx context.Context, vkeID, nodePoolID string, nodePoolLabelReq *NodePoolLabelReq) (*NodePoolLabel, *http.Response, error) { //nolint:lll,dupl
+	if err := k.client.validate(nodePoolLabelReq); err != nil {
+		return nil, nil, err
+	}
+
 	uri := fmt.Sprintf("%s/%s/node-pools/%s/labels", vkePath, vkeID, nodePoolID)
 
 	req, err := k.client.NewRequest(ctx, http.MethodPost, uri, nodePoolLabelReq)
@@ -506,6 +650,10 @@ func (k *KubernetesHandler) ListNodePoolTaints(ctx context.Context, vkeID, nodeP
 
 // CreateNodePoolTaint creates a taint on a node pool
 func (k *KubernetesHandler) CreateNodePoolTaint(ctx context.Context, vkeID, nodePoolID string, nodePoolTaintReq *NodePoolTaintReq) (*NodePoolTaint, *http.Response, error) { //nolint:lll,dupl
+	if err := k.client.validate(nodePoolTaintReq); err != nil {
+		return nil, nil, err
+	}
+
 	uri := fmt.Sprintf("%s/%s/node-pools/%s/taints", vkePath, vkeID, nodePoolID)
 
 	req, err := k.client.NewRequest(ctx, http.MethodPost, uri, nodePoolTaintReq)
@@ -0,0 +1,191 @@
+package govultr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const clusterDeletionTokenLength = 16
+
+// ErrClusterDeletionNotConfirmed is returned by DeleteClusterConfirmed when the token
+// does not match the current inventory of the cluster
+var ErrClusterDeletionNotConfirmed = errors.New("cluster deletion token does not match the current inventory")
+
+// ClusterInventoryNode is a node instance of the cluster and the pool it belongs to
+type ClusterInventoryNode struct {
+	NodePoolID    string
+	NodePoolLabel string
+	Node          Node
+}
+
+// ClusterDeletionInventory is a best effort list of the resources linked to a VKE
+// cluster. DeleteClusterWithResources deletes what the cluster created, which the API
+// does not expose, so the inventory is inferred: load balancers are listed when they
+// balance across a node of the cluster or their label contains the cluster ID, and
+// block storage when it is attached to a node or its label contains the cluster ID.
+// A load balancer a user pointed at a node is listed although it is not deleted, and
+// a detached volume the cluster created without the cluster ID in its label is missed.
+//
+// Token identifies the listed resources and must be passed back to
+// DeleteClusterConfirmed. It confirms that the listing did not change since it was
+// reviewed, not the exact set of resources the API deletes.
+type ClusterDeletionInventory struct {
+	ClusterID     string
+	Label         string
+	Nodes         []ClusterInventoryNode
+	LoadBalancers []LoadBalancer
+	BlockStorages []BlockStorage
+	// FirewallGroup is listed for reference; deleting the cluster keeps it
+	FirewallGroup *FirewallGroup
+	Token         string
+}
+
+// String renders the inventory for people to review before confirming
+func (c *ClusterDeletionInventory) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Deleting cluster %s (%s) is expected to affect (best effort):\n", c.Label, c.ClusterID)
+
+	for idx := range c.Nodes {
+		n := &c.Nodes[idx]
+		fmt.Fprintf(&b, "  node %s %s (pool %s)\n", n.Node.ID, n.Node.Label, n.NodePoolLabel)
+	}
+	for idx := range c.LoadBalancers {
+		fmt.Fprintf(&b, "  load balancer %s %s\n", c.LoadBalancers[idx].ID, c.LoadBalancers[idx].Label)
+	}
+	for idx := range c.BlockStorages {
+		bs := &c.BlockStorages[idx]
+		fmt.Fprintf(&b, "  block storage %s %s (%d GB)\n", bs.ID, bs.Label, bs.SizeGB)
+	}
+	if c.FirewallGroup != nil {
+		fmt.Fprintf(&b, "Kept:\n  firewall group %s %s\n", c.FirewallGroup.ID, c.FirewallGroup.Description)
+	}
+	fmt.Fprintf(&b, "Confirm with token %s\n", c.Token)
+
+	return b.String()
+}
+
+// PreviewClusterDeletion returns a best effort inventory of the resources that
+// DeleteClusterWithResources would affect and the firewall group it keeps, together
+// with the token needed to confirm the deletion
+func (k *KubernetesHandler) PreviewClusterDeletion(ctx context.Context, vkeID string) (*ClusterDeletionInventory, error) {
+	cluster, _, err := k.GetCluster(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &ClusterDeletionInventory{ClusterID: cluster.ID, Label: cluster.Label}
+
+	pools, err := listAll(func(options *ListOptions) ([]NodePool, *Meta, *http.Response, error) {
+		return k.ListNodePools(ctx, vkeID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]bool)
+	for idx := range pools {
+		for _, node := range pools[idx].Nodes {
+			nodes[node.ID] = true
+			inventory.Nodes = append(inventory.Nodes, ClusterInventoryNode{
+				NodePoolID:    pools[idx].ID,
+				NodePoolLabel: pools[idx].Label,
+				Node:          node,
+			})
+		}
+	}
+
+	if err := k.inventoryLinkedResources(ctx, inventory, nodes); err != nil {
+		return nil, err
+	}
+
+	if cluster.FirewallGroupID != "" {
+		if inventory.FirewallGroup, _, err = k.client.FirewallGroup.Get(ctx, cluster.FirewallGroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	inventory.Token = inventory.token()
+	return inventory, nil
+}
+
+// inventoryLinkedResources adds the load balancers and block storage that use one of the
+// nodes or carry the cluster ID in their label
+func (k *KubernetesHandler) inventoryLinkedResources(ctx context.Context, inventory *ClusterDeletionInventory, nodes map[string]bool) error { //nolint:lll
+	loadBalancers, err := listAll(func(options *ListOptions) ([]LoadBalancer, *Meta, *http.Response, error) {
+		return k.client.LoadBalancer.List(ctx, options)
+	})
+	if err != nil {
+		return err
+	}
+
+	for idx := range loadBalancers {
+		linked := strings.Contains(loadBalancers[idx].Label, inventory.ClusterID)
+		for _, instance := range loadBalancers[idx].Instances {
+			linked = linked || nodes[instance]
+		}
+		if linked {
+			inventory.LoadBalancers = append(inventory.LoadBalancers, loadBalancers[idx])
+		}
+	}
+
+	volumes, err := listAll(func(options *ListOptions) ([]BlockStorage, *Meta, *http.Response, error) {
+		return k.client.BlockStorage.List(ctx, options)
+	})
+	if err != nil {
+		return err
+	}
+
+	for idx := range volumes {
+		if nodes[volumes[idx].AttachedToInstance] || strings.Contains(volumes[idx].Label, inventory.ClusterID) {
+			inventory.BlockStorages = append(inventory.BlockStorages, volumes[idx])
+		}
+	}
+
+	return nil
+}
+
+// token hashes the IDs of every listed resource, so any resource that is added or
+// removed after the preview produces a different token
+func (c *ClusterDeletionInventory) token() string {
+	ids := []string{"cluster:" + c.ClusterID}
+	for idx := range c.Nodes {
+		ids = append(ids, "node:"+c.Nodes[idx].Node.ID)
+	}
+	for idx := range c.LoadBalancers {
+		ids = append(ids, "load-balancer:"+c.LoadBalancers[idx].ID)
+	}
+	for idx := range c.BlockStorages {
+		ids = append(ids, "block-storage:"+c.BlockStorages[idx].ID)
+	}
+	if c.FirewallGroup != nil {
+		ids = append(ids, "firewall-group:"+c.FirewallGroup.ID)
+	}
+	sort.Strings(ids)
+
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+	return hex.EncodeToString(sum[:])[:clusterDeletionTokenLength]
+}
+
+// DeleteClusterConfirmed deletes a cluster and its linked resources with
+// DeleteClusterWithResources, but only when token matches a fresh preview of the
+// cluster. A mismatch means listed resources were added or removed since the caller
+// reviewed the inventory; ErrClusterDeletionNotConfirmed is returned with the new inventory so
+// it can be reviewed again.
+func (k *KubernetesHandler) DeleteClusterConfirmed(ctx context.Context, vkeID, token string) (*ClusterDeletionInventory, error) {
+	inventory, err := k.PreviewClusterDeletion(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != inventory.Token {
+		return inventory, ErrClusterDeletionNotConfirmed
+	}
+
+	return inventory, k.DeleteClusterWithResources(ctx, vkeID)
+}
@@ -0,0 +1,118 @@
+package govultr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// handleClusterInventory serves a cluster with two nodes, a load balancer and a volume
+// attached to one node, a load balancer and a volume named after the cluster and
+// resources that belong to something else. The extra volume is
+// attached to the first node when attachVolume is set.
+func handleClusterInventory(attachVolume *bool) {
+	mux.HandleFunc("/v2/kubernetes/clusters/abc", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"abc","label":"production","firewall_group_id":"fw"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"node_pools":[{"id":"p1","label":"web","nodes":[
+			{"id":"n1","label":"web-1","status":"active"},{"id":"n2","label":"web-2","status":"active"}]}
+		],"meta":{"total":1,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/load-balancers", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"load_balancers":[
+			{"id":"lb1","label":"ingress","instances":["n1","n2"]},
+			{"id":"lb2","label":"other","instances":["i9"]},
+			{"id":"lb3","label":"vke-abc-pending","instances":[]}
+		],"meta":{"total":3,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/blocks", func(writer http.ResponseWriter, request *http.Request) {
+		extra := ""
+		if *attachVolume {
+			extra = "n1"
+		}
+		fmt.Fprintf(writer, `{"blocks":[
+			{"id":"bs1","label":"pvc-1","size_gb":10,"attached_to_instance":"n2"},
+			{"id":"bs2","label":"pvc-2","size_gb":20,"attached_to_instance":%q},
+			{"id":"bs3","label":"db","size_gb":40,"attached_to_instance":"i9"},
+			{"id":"bs4","label":"pvc-abc-data","size_gb":5,"attached_to_instance":""}
+		],"meta":{"total":4,"links":{}}}`, extra)
+	})
+	mux.HandleFunc("/v2/firewalls/fw", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_group":{"id":"fw","description":"vke production"}}`)
+	})
+}
+
+func TestKubernetesHandler_PreviewClusterDeletion(t *testing.T) {
+	setup()
+	defer teardown()
+
+	attach := false
+	handleClusterInventory(&attach)
+
+	inventory, err := client.Kubernetes.PreviewClusterDeletion(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Kubernetes.PreviewClusterDeletion returned %+v", err)
+	}
+
+	expected := "Deleting cluster production (abc) is expected to affect (best effort):\n" +
+		"  node n1 web-1 (pool web)\n" +
+		"  node n2 web-2 (pool web)\n" +
+		"  load balancer lb1 ingress\n" +
+		"  load balancer lb3 vke-abc-pending\n" +
+		"  block storage bs1 pvc-1 (10 GB)\n" +
+		"  block storage bs4 pvc-abc-data (5 GB)\n" +
+		"Kept:\n" +
+		"  firewall group fw vke production\n" +
+		"Confirm with token " + inventory.Token + "\n"
+	if inventory.String() != expected {
+		t.Errorf("ClusterDeletionInventory.String returned\n%s\nexpected\n%s", inventory, expected)
+	}
+
+	if len(inventory.Token) != clusterDeletionTokenLength {
+		t.Errorf("Kubernetes.PreviewClusterDeletion returned token %q", inventory.Token)
+	}
+
+	again, err := client.Kubernetes.PreviewClusterDeletion(ctx, "abc")
+	if err != nil || again.Token != inventory.Token {
+		t.Errorf("Kubernetes.PreviewClusterDeletion token changed without changes: %q != %q (%v)", again.Token, inventory.Token, err)
+	}
+}
+
+func TestKubernetesHandler_DeleteClusterConfirmed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	attach := false
+	handleClusterInventory(&attach)
+
+	deleted := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/delete-with-linked-resources", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodDelete)
+		}
+		deleted++
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	preview, err := client.Kubernetes.PreviewClusterDeletion(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Kubernetes.PreviewClusterDeletion returned %+v", err)
+	}
+
+	// a volume attached after the preview invalidates the token
+	attach = true
+	inventory, err := client.Kubernetes.DeleteClusterConfirmed(ctx, "abc", preview.Token)
+	if !errors.Is(err, ErrClusterDeletionNotConfirmed) || deleted != 0 {
+		t.Fatalf("Kubernetes.DeleteClusterConfirmed returned %+v after %d deletes", err, deleted)
+	}
+	if !strings.Contains(inventory.String(), "block storage bs2 pvc-2 (20 GB)") {
+		t.Errorf("Kubernetes.DeleteClusterConfirmed returned inventory\n%s", inventory)
+	}
+
+	if _, err := client.Kubernetes.DeleteClusterConfirmed(ctx, "abc", inventory.Token); err != nil || deleted != 1 {
+		t.Errorf("Kubernetes.DeleteClusterConfirmed returned %+v after %d deletes", err, deleted)
+	}
+}
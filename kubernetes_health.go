@@ -0,0 +1,236 @@
+package govultr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ClusterHealthCheck identifies the check that raised a ClusterHealthIssue
+type ClusterHealthCheck string
+
+// Checks run by GetClusterHealth
+const (
+	ClusterHealthControlPlane   ClusterHealthCheck = "control-plane"
+	ClusterHealthNodePoolStatus ClusterHealthCheck = "node-pool-status"
+	ClusterHealthNodeCount      ClusterHealthCheck = "node-count"
+	ClusterHealthNodeStatus     ClusterHealthCheck = "node-status"
+	ClusterHealthAutoScaler     ClusterHealthCheck = "auto-scaler"
+	ClusterHealthUpgrade        ClusterHealthCheck = "upgrade"
+	ClusterHealthVersion        ClusterHealthCheck = "version"
+)
+
+// ClusterHealthSeverity ranks how urgently an issue should be addressed
+type ClusterHealthSeverity string
+
+// Severities of cluster health issues, from least to most urgent
+const (
+	ClusterHealthInfo     ClusterHealthSeverity = "info"
+	ClusterHealthWarning  ClusterHealthSeverity = "warning"
+	ClusterHealthCritical ClusterHealthSeverity = "critical"
+)
+
+// clusterHealthSeverities orders the severities from least to most urgent
+var clusterHealthSeverities = []ClusterHealthSeverity{ClusterHealthInfo, ClusterHealthWarning, ClusterHealthCritical}
+
+// rank returns the position of s in clusterHealthSeverities, -1 for no severity
+func (s ClusterHealthSeverity) rank() int {
+	for idx, severity := range clusterHealthSeverities {
+		if severity == s {
+			return idx
+		}
+	}
+	return -1
+}
+
+// ClusterHealthIssue is a single problem or notice found in a cluster
+type ClusterHealthIssue struct {
+	Check    ClusterHealthCheck
+	Severity ClusterHealthSeverity
+	// NodePoolID and NodeID are set for issues about a node pool or node
+	NodePoolID string
+	NodeID     string
+	Message    string
+}
+
+func (c *ClusterHealthIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", c.Severity, c.Check, c.Message)
+}
+
+// NodePoolHealth summarizes the state of one node pool
+type NodePoolHealth struct {
+	ID           string
+	Label        string
+	Status       VKEStatus
+	NodeQuantity int
+	Nodes        int
+	AutoScaler   bool
+	MinNodes     int
+	MaxNodes     int
+	// NotActive lists the nodes whose status is not active
+	NotActive []Node
+}
+
+// ClusterHealth is the health report of a VKE cluster
+type ClusterHealth struct {
+	ClusterID         string
+	Label             string
+	Status            VKEStatus
+	Version           string
+	VersionSupported  bool
+	AvailableUpgrades []string
+	NodePools         []NodePoolHealth
+	Issues            []ClusterHealthIssue
+}
+
+// Severity returns the most urgent severity among the issues, or an empty string
+// when there are none
+func (c *ClusterHealth) Severity() ClusterHealthSeverity {
+	var worst ClusterHealthSeverity
+	for idx := range c.Issues {
+		if c.Issues[idx].Severity.rank() > worst.rank() {
+			worst = c.Issues[idx].Severity
+		}
+	}
+	return worst
+}
+
+// Healthy reports whether the cluster has no warning or critical issues
+func (c *ClusterHealth) Healthy() bool {
+	return c.Severity().rank() < ClusterHealthWarning.rank()
+}
+
+// String renders the report as a short summary for terminals
+func (c *ClusterHealth) String() string {
+	var b strings.Builder
+
+	state := "healthy"
+	if !c.Healthy() {
+		state = "unhealthy"
+	}
+	fmt.Fprintf(&b, "Cluster %s (%s) is %s: status %s, version %s\n", c.Label, c.ClusterID, state, c.Status, c.Version)
+
+	for idx := range c.NodePools {
+		pool := &c.NodePools[idx]
+		fmt.Fprintf(&b, "  pool %s: %d/%d nodes, %d not active\n", pool.Label, pool.Nodes, pool.NodeQuantity, len(pool.NotActive))
+	}
+
+	for idx := range c.Issues {
+		fmt.Fprintf(&b, "  %s\n", c.Issues[idx].String())
+	}
+
+	return b.String()
+}
+
+// GetClusterHealth checks the control plane, node pools and nodes of a cluster and
+// whether its version is still supported or can be upgraded
+func (k *KubernetesHandler) GetClusterHealth(ctx context.Context, vkeID string) (*ClusterHealth, error) {
+	cluster, _, err := k.GetCluster(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := listAll(func(options *ListOptions) ([]NodePool, *Meta, *http.Response, error) {
+		return k.ListNodePools(ctx, vkeID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	upgrades, _, err := k.GetUpgrades(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, _, err := k.GetVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return AssessClusterHealth(cluster, pools, upgrades, versions.Versions), nil
+}
+
+// AssessClusterHealth builds a health report from state already fetched from the API
+func AssessClusterHealth(cluster *Cluster, pools []NodePool, upgrades, versions []string) *ClusterHealth {
+	health := &ClusterHealth{
+		ClusterID:         cluster.ID,
+		Label:             cluster.Label,
+		Status:            cluster.Status,
+		Version:           cluster.Version,
+		VersionSupported:  containsString(versions, cluster.Version),
+		AvailableUpgrades: upgrades,
+	}
+
+	if cluster.Status != VKEStatusActive {
+		health.addIssuef(ClusterHealthControlPlane, ClusterHealthCritical, "", "", "control plane status is %q", cluster.Status)
+	}
+
+	for idx := range pools {
+		health.assessNodePool(&pools[idx])
+	}
+
+	if !health.VersionSupported {
+		health.addIssuef(ClusterHealthVersion, ClusterHealthWarning, "", "",
+			"version %s is no longer offered by VKE", cluster.Version)
+	}
+	if len(upgrades) > 0 {
+		health.addIssuef(ClusterHealthUpgrade, ClusterHealthInfo, "", "",
+			"upgrades available: %s", strings.Join(upgrades, ", "))
+	}
+
+	return health
+}
+
+func (c *ClusterHealth) assessNodePool(pool *NodePool) {
+	ph := NodePoolHealth{
+		ID:           pool.ID,
+		Label:        pool.Label,
+		Status:       pool.Status,
+		NodeQuantity: pool.NodeQuantity,
+		Nodes:        len(pool.Nodes),
+		AutoScaler:   pool.AutoScaler,
+		MinNodes:     pool.MinNodes,
+		MaxNodes:     pool.MaxNodes,
+	}
+
+	if pool.Status != "" && pool.Status != VKEStatusActive {
+		c.addIssuef(ClusterHealthNodePoolStatus, ClusterHealthWarning, pool.ID, "", "pool %s status is %q", pool.Label, pool.Status)
+	}
+
+	if ph.Nodes != pool.NodeQuantity {
+		c.addIssuef(ClusterHealthNodeCount, ClusterHealthWarning, pool.ID, "",
+			"pool %s has %d nodes, expected %d", pool.Label, ph.Nodes, pool.NodeQuantity)
+	}
+
+	for _, node := range pool.Nodes {
+		if node.Status == VKEStatusActive {
+			continue
+		}
+		ph.NotActive = append(ph.NotActive, node)
+		c.addIssuef(ClusterHealthNodeStatus, ClusterHealthWarning, pool.ID, node.ID,
+			"node %s in pool %s status is %q", node.Label, pool.Label, node.Status)
+	}
+
+	if ph.Nodes > 0 && len(ph.NotActive) == ph.Nodes {
+		c.addIssuef(ClusterHealthNodeStatus, ClusterHealthCritical, pool.ID, "", "pool %s has no active nodes", pool.Label)
+	}
+
+	if pool.AutoScaler && (pool.NodeQuantity < pool.MinNodes || pool.NodeQuantity > pool.MaxNodes) {
+		c.addIssuef(ClusterHealthAutoScaler, ClusterHealthWarning, pool.ID, "",
+			"pool %s node quantity %d is outside the auto scaler bounds %d-%d",
+			pool.Label, pool.NodeQuantity, pool.MinNodes, pool.MaxNodes)
+	}
+
+	c.NodePools = append(c.NodePools, ph)
+}
+
+func (c *ClusterHealth) addIssuef(check ClusterHealthCheck, severity ClusterHealthSeverity, poolID, nodeID, format string, args ...interface{}) { //nolint:lll
+	c.Issues = append(c.Issues, ClusterHealthIssue{
+		Check:      check,
+		Severity:   severity,
+		NodePoolID: poolID,
+		NodeID:     nodeID,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
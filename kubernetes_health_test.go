@@ -0,0 +1,97 @@
+package govultr
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestKubernetesHandler_GetClusterHealth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/abc", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"abc","label":"production","version":"v1.29.4+1","status":"active"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"node_pools":[
+			{"id":"p1","label":"web","status":"active","node_quantity":2,"nodes":[
+				{"id":"n1","label":"web-1","status":"active"},{"id":"n2","label":"web-2","status":"active"}]}
+		],"meta":{"total":1,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/available-upgrades", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"available_upgrades":[]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/versions", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"versions":["v1.30.1+1","v1.29.4+1"]}`)
+	})
+
+	health, err := client.Kubernetes.GetClusterHealth(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Kubernetes.GetClusterHealth returned %+v", err)
+	}
+
+	if !health.Healthy() || len(health.Issues) != 0 || !health.VersionSupported || health.Severity() != "" {
+		t.Errorf("Kubernetes.GetClusterHealth returned %+v", health)
+	}
+
+	expected := "Cluster production (abc) is healthy: status active, version v1.29.4+1\n  pool web: 2/2 nodes, 0 not active\n"
+	if health.String() != expected {
+		t.Errorf("ClusterHealth.String returned\n%s\nexpected\n%s", health, expected)
+	}
+}
+
+func TestAssessClusterHealth(t *testing.T) {
+	cluster := &Cluster{ID: "abc", Label: "production", Version: "v1.27.9+1", Status: "pending"}
+	pools := []NodePool{
+		{
+			ID: "p1", Label: "web", Status: VKEStatusActive, NodeQuantity: 3, AutoScaler: true, MinNodes: 4, MaxNodes: 6,
+			Nodes: []Node{{ID: "n1", Label: "web-1", Status: VKEStatusActive}, {ID: "n2", Label: "web-2", Status: "recycling"}},
+		},
+		{
+			ID: "p2", Label: "batch", Status: "pending", NodeQuantity: 1,
+			Nodes: []Node{{ID: "n3", Label: "batch-1", Status: "pending"}},
+		},
+	}
+
+	health := AssessClusterHealth(cluster, pools, []string{"v1.28.5+1"}, []string{"v1.29.4+1", "v1.28.5+1"})
+
+	expected := []ClusterHealthIssue{
+		{Check: ClusterHealthControlPlane, Severity: ClusterHealthCritical, Message: `control plane status is "pending"`},
+		{Check: ClusterHealthNodeCount, Severity: ClusterHealthWarning, NodePoolID: "p1", Message: "pool web has 2 nodes, expected 3"},
+		{
+			Check: ClusterHealthNodeStatus, Severity: ClusterHealthWarning, NodePoolID: "p1", NodeID: "n2",
+			Message: `node web-2 in pool web status is "recycling"`,
+		},
+		{
+			Check: ClusterHealthAutoScaler, Severity: ClusterHealthWarning, NodePoolID: "p1",
+			Message: "pool web node quantity 3 is outside the auto scaler bounds 4-6",
+		},
+		{Check: ClusterHealthNodePoolStatus, Severity: ClusterHealthWarning, NodePoolID: "p2", Message: `pool batch status is "pending"`},
+		{
+			Check: ClusterHealthNodeStatus, Severity: ClusterHealthWarning, NodePoolID: "p2", NodeID: "n3",
+			Message: `node batch-1 in pool batch status is "pending"`,
+		},
+		{Check: ClusterHealthNodeStatus, Severity: ClusterHealthCritical, NodePoolID: "p2", Message: "pool batch has no active nodes"},
+		{Check: ClusterHealthVersion, Severity: ClusterHealthWarning, Message: "version v1.27.9+1 is no longer offered by VKE"},
+		{Check: ClusterHealthUpgrade, Severity: ClusterHealthInfo, Message: "upgrades available: v1.28.5+1"},
+	}
+	if !reflect.DeepEqual(health.Issues, expected) {
+		t.Errorf("AssessClusterHealth returned issues\n%+v\nexpected\n%+v", health.Issues, expected)
+	}
+
+	if health.Healthy() || health.Severity() != ClusterHealthCritical {
+		t.Errorf("AssessClusterHealth severity = %q", health.Severity())
+	}
+
+	if len(health.NodePools) != 2 || health.NodePools[0].Nodes != 2 || len(health.NodePools[0].NotActive) != 1 {
+		t.Errorf("AssessClusterHealth node pools = %+v", health.NodePools)
+	}
+
+	upgradeOnly := AssessClusterHealth(&Cluster{Version: "v1.29.4+1", Status: VKEStatusActive}, nil,
+		[]string{"v1.30.1+1"}, []string{"v1.30.1+1", "v1.29.4+1"})
+	if !upgradeOnly.Healthy() || upgradeOnly.Severity() != ClusterHealthInfo {
+		t.Errorf("AssessClusterHealth with only an upgrade available returned %+v", upgradeOnly)
+	}
+}
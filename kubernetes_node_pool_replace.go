@@ -0,0 +1,149 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// NodePoolReplaceOptions configures ReplaceNodePool
+type NodePoolReplaceOptions struct {
+	// Plan is the plan of the replacement pool and is required
+	Plan string
+	// Label of the replacement pool. Defaults to the old label suffixed with the plan
+	// as labels must be unique within a cluster.
+	Label string
+	// Surge is the number of nodes added to the replacement pool per step. The old
+	// pool is scaled down by the same amount once they are ready. Zero creates the
+	// replacement at full size before the old pool is removed.
+	Surge int
+	Wait  *WaitOptions
+}
+
+// ReplaceNodePool moves a node pool to another plan. A replacement pool with the same
+// labels, taints, tag, user data and auto scaler bounds is created and grown in steps
+// of opts.Surge nodes while the old pool shrinks, so capacity never drops below the old
+// node count. The old pool is deleted once the replacement is complete.
+//
+// The auto scaler is paused on both pools during the move and enabled on the
+// replacement at the end. If a step fails both pools are left in place so the move can
+// be inspected and finished by hand; the returned pool is the replacement, if created.
+func (k *KubernetesHandler) ReplaceNodePool(ctx context.Context, vkeID, nodePoolID string, opts *NodePoolReplaceOptions) (*NodePool, error) { //nolint:lll
+	if opts == nil || opts.Plan == "" {
+		return nil, errors.New("a plan is required to replace a node pool")
+	}
+
+	old, _, err := k.GetNodePool(ctx, vkeID, nodePoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := k.exportNodePool(ctx, vkeID, old)
+	if err != nil {
+		return nil, err
+	}
+
+	target := old.NodeQuantity
+	if target < 1 {
+		target = 1
+	}
+
+	req.Plan = opts.Plan
+	req.Label = opts.Label
+	if req.Label == "" {
+		req.Label = old.Label + "-" + opts.Plan
+	}
+	req.NodeQuantity = surgeStep(0, opts.Surge, target)
+	req.AutoScaler = BoolToBoolPtr(false)
+	req.MinNodes, req.MaxNodes = 0, 0
+
+	replacement, _, err := k.CreateNodePool(ctx, vkeID, req)
+	if err != nil {
+		return nil, fmt.Errorf("creating replacement for node pool %s: %w", nodePoolID, err)
+	}
+
+	if old.AutoScaler {
+		if _, _, err := k.UpdateNodePool(ctx, vkeID, old.ID, &NodePoolReqUpdate{AutoScaler: BoolToBoolPtr(false)}); err != nil {
+			return replacement, fmt.Errorf("pausing auto scaler of node pool %s: %w", old.ID, err)
+		}
+	}
+
+	if err := k.surgeNodePool(ctx, vkeID, old.ID, replacement.ID, req.NodeQuantity, target, opts); err != nil {
+		return replacement, err
+	}
+
+	if err := k.DeleteNodePool(ctx, vkeID, old.ID); err != nil {
+		return replacement, fmt.Errorf("deleting node pool %s: %w", old.ID, err)
+	}
+
+	if old.AutoScaler {
+		update := &NodePoolReqUpdate{AutoScaler: BoolToBoolPtr(true), MinNodes: old.MinNodes, MaxNodes: old.MaxNodes}
+		if _, _, err := k.UpdateNodePool(ctx, vkeID, replacement.ID, update); err != nil {
+			return replacement, fmt.Errorf("enabling auto scaler of node pool %s: %w", replacement.ID, err)
+		}
+	}
+
+	if pool, _, err := k.GetNodePool(ctx, vkeID, replacement.ID); err == nil {
+		replacement = pool
+	}
+
+	return replacement, nil
+}
+
+// surgeNodePool grows the replacement pool to target, waiting for its nodes after every
+// step and then shrinking the old pool by the number of ready replacement nodes. A pool
+// cannot have zero nodes, so the old pool keeps one node until it is deleted.
+func (k *KubernetesHandler) surgeNodePool(ctx context.Context, vkeID, oldID, newID string, count, target int, opts *NodePoolReplaceOptions) error { //nolint:lll
+	for {
+		if err := k.waitForNodePool(ctx, vkeID, newID, count, opts.Wait); err != nil {
+			return err
+		}
+
+		if count >= target {
+			return nil
+		}
+
+		remaining := target - count
+		if _, _, err := k.UpdateNodePool(ctx, vkeID, oldID, &NodePoolReqUpdate{NodeQuantity: remaining}); err != nil {
+			return fmt.Errorf("scaling node pool %s down to %d: %w", oldID, remaining, err)
+		}
+
+		count = surgeStep(count, opts.Surge, target)
+		if _, _, err := k.UpdateNodePool(ctx, vkeID, newID, &NodePoolReqUpdate{NodeQuantity: count}); err != nil {
+			return fmt.Errorf("scaling node pool %s up to %d: %w", newID, count, err)
+		}
+	}
+}
+
+// surgeStep returns the node count after growing current by surge without passing target
+func surgeStep(current, surge, target int) int {
+	if surge <= 0 || current+surge > target {
+		return target
+	}
+	return current + surge
+}
+
+// waitForNodePool waits until the pool has at least count nodes and all of them are active
+func (k *KubernetesHandler) waitForNodePool(ctx context.Context, vkeID, nodePoolID string, count int, wait *WaitOptions) error {
+	desc := fmt.Sprintf("%d nodes of node pool %s to be active", count, nodePoolID)
+	return waitFor(ctx, wait, desc, func(ctx context.Context) (bool, error) {
+		nodes, err := listAll(func(options *ListOptions) ([]Node, *Meta, *http.Response, error) {
+			return k.ListWorkerNodes(ctx, vkeID, nodePoolID, options)
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if len(nodes) < count {
+			return false, nil
+		}
+		for idx := range nodes {
+			if nodes[idx].Status != VKEStatusActive {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
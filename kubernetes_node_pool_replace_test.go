@@ -0,0 +1,169 @@
+package govultr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeReplacePools serves the node pool endpoints used by ReplaceNodePool and records
+// every change made to the pools
+type fakeReplacePools struct {
+	pools   map[string]*NodePool
+	created *NodePoolReq
+	events  []string
+	polls   int
+}
+
+func newFakeReplacePools(t *testing.T) *fakeReplacePools {
+	f := &fakeReplacePools{pools: map[string]*NodePool{
+		"old": {
+			ID: "old", Label: "web", Plan: "vc2-2c-4gb", Tag: "frontend", NodeQuantity: 5,
+			AutoScaler: true, MinNodes: 2, MaxNodes: 6, UserData: "I2Nsb3VkLWNvbmZpZw==",
+		},
+	}}
+
+	base := "/v2/kubernetes/clusters/abc/node-pools"
+	mux.HandleFunc(base, func(writer http.ResponseWriter, request *http.Request) {
+		f.created = &NodePoolReq{}
+		if err := json.NewDecoder(request.Body).Decode(f.created); err != nil {
+			t.Fatal(err)
+		}
+		f.pools["new"] = &NodePool{ID: "new", Label: f.created.Label, Plan: f.created.Plan, NodeQuantity: f.created.NodeQuantity}
+		f.events = append(f.events, fmt.Sprintf("create new %d", f.created.NodeQuantity))
+		_ = json.NewEncoder(writer).Encode(&vkeNodePoolBase{NodePool: f.pools["new"]})
+	})
+	mux.HandleFunc(base+"/old/labels", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"labels":[{"id":"l1","key":"tier","value":"web"}]}`)
+	})
+	mux.HandleFunc(base+"/old/taints", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"taints":[{"id":"t1","key":"dedicated","value":"web","effect":"NoSchedule"}]}`)
+	})
+	mux.HandleFunc(base+"/new/nodes", func(writer http.ResponseWriter, request *http.Request) {
+		// every other poll reports the newest node as still provisioning
+		f.polls++
+		nodes := make([]Node, f.pools["new"].NodeQuantity)
+		for idx := range nodes {
+			nodes[idx] = Node{ID: fmt.Sprintf("node-%d", idx), Status: VKEStatusActive}
+		}
+		if f.polls%2 == 1 {
+			nodes[len(nodes)-1].Status = "pending"
+		}
+		_ = json.NewEncoder(writer).Encode(&vkeWorkerNodesBase{WorkerNodes: nodes, Meta: &Meta{Total: len(nodes), Links: &Links{}}})
+	})
+	mux.HandleFunc(base+"/", func(writer http.ResponseWriter, request *http.Request) {
+		id := strings.TrimPrefix(request.URL.Path, base+"/")
+		pool, ok := f.pools[id]
+		if !ok {
+			http.NotFound(writer, request)
+			return
+		}
+
+		switch request.Method {
+		case http.MethodPatch:
+			var update NodePoolReqUpdate
+			if err := json.NewDecoder(request.Body).Decode(&update); err != nil {
+				t.Fatal(err)
+			}
+			f.events = append(f.events, fmt.Sprintf("update %s %s", id, describeNodePoolUpdate(&update)))
+			if update.NodeQuantity > 0 {
+				pool.NodeQuantity = update.NodeQuantity
+			}
+			if update.AutoScaler != nil {
+				pool.AutoScaler, pool.MinNodes, pool.MaxNodes = *update.AutoScaler, update.MinNodes, update.MaxNodes
+			}
+		case http.MethodDelete:
+			f.events = append(f.events, "delete "+id)
+			delete(f.pools, id)
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(writer).Encode(&vkeNodePoolBase{NodePool: pool})
+	})
+
+	return f
+}
+
+func describeNodePoolUpdate(u *NodePoolReqUpdate) string {
+	if u.AutoScaler != nil {
+		return fmt.Sprintf("auto_scaler=%t %d-%d", *u.AutoScaler, u.MinNodes, u.MaxNodes)
+	}
+	return fmt.Sprintf("quantity=%d", u.NodeQuantity)
+}
+
+func TestKubernetesHandler_ReplaceNodePool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeReplacePools(t)
+
+	pool, err := client.Kubernetes.ReplaceNodePool(ctx, "abc", "old", &NodePoolReplaceOptions{
+		Plan:  "vc2-4c-8gb",
+		Surge: 2,
+		Wait:  &WaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Kubernetes.ReplaceNodePool returned %+v", err)
+	}
+
+	expectedReq := &NodePoolReq{
+		NodeQuantity: 2,
+		Label:        "web-vc2-4c-8gb",
+		Plan:         "vc2-4c-8gb",
+		Tag:          "frontend",
+		AutoScaler:   BoolToBoolPtr(false),
+		Labels:       map[string]string{"tier": "web"},
+		Taints:       []Taint{{Key: "dedicated", Value: "web", Effect: TaintEffectNoSchedule}},
+		UserData:     "I2Nsb3VkLWNvbmZpZw==",
+	}
+	if !reflect.DeepEqual(fake.created, expectedReq) {
+		t.Errorf("Kubernetes.ReplaceNodePool created %+v, expected %+v", fake.created, expectedReq)
+	}
+
+	expectedEvents := []string{
+		"create new 2",
+		"update old auto_scaler=false 0-0",
+		"update old quantity=3",
+		"update new quantity=4",
+		"update old quantity=1",
+		"update new quantity=5",
+		"delete old",
+		"update new auto_scaler=true 2-6",
+	}
+	if !reflect.DeepEqual(fake.events, expectedEvents) {
+		t.Errorf("Kubernetes.ReplaceNodePool made changes\n%s\nexpected\n%s", strings.Join(fake.events, "\n"), strings.Join(expectedEvents, "\n"))
+	}
+
+	if pool.ID != "new" || pool.NodeQuantity != 5 || !pool.AutoScaler {
+		t.Errorf("Kubernetes.ReplaceNodePool returned %+v", pool)
+	}
+}
+
+func TestKubernetesHandler_ReplaceNodePoolWithoutSurge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeReplacePools(t)
+	fake.pools["old"].AutoScaler = false
+
+	_, err := client.Kubernetes.ReplaceNodePool(ctx, "abc", "old", &NodePoolReplaceOptions{
+		Plan:  "vc2-4c-8gb",
+		Label: "web-large",
+		Wait:  &WaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Kubernetes.ReplaceNodePool returned %+v", err)
+	}
+
+	if fake.created.Label != "web-large" || !reflect.DeepEqual(fake.events, []string{"create new 5", "delete old"}) {
+		t.Errorf("Kubernetes.ReplaceNodePool created %s and made changes %v", fake.created.Label, fake.events)
+	}
+
+	if _, err := client.Kubernetes.ReplaceNodePool(ctx, "abc", "old", &NodePoolReplaceOptions{}); err == nil {
+		t.Error("Kubernetes.ReplaceNodePool expected an error without a plan")
+	}
+}
@@ -0,0 +1,312 @@
+package govultr
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Effects a node pool taint can have
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+const (
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+	labelSyntax          = "must start and end with an alphanumeric character and contain only alphanumerics, '-', '_' or '.'"
+)
+
+var (
+	labelNameRegexp   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// Validate checks the label against the Kubernetes label key and value syntax
+func (n *NodePoolLabelReq) Validate() error {
+	v := newValidation("NodePoolLabelReq")
+	validateLabelKey(v, "key", n.Key)
+	validateLabelValue(v, "value", n.Value)
+	return v.err()
+}
+
+// Validate checks the taint against the Kubernetes taint syntax
+func (n *NodePoolTaintReq) Validate() error {
+	v := newValidation("NodePoolTaintReq")
+	validateTaint(v, "", &Taint{Key: n.Key, Value: n.Value, Effect: n.Effect})
+	return v.err()
+}
+
+// validateLabelKey checks a key of the form [prefix/]name where the optional prefix
+// is a DNS subdomain and the name is at most 63 alphanumerics, '-', '_' or '.'
+func validateLabelKey(v *validation, field, key string) {
+	if key == "" {
+		v.addf(field, "is required")
+		return
+	}
+
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		name = rest
+		switch {
+		case prefix == "":
+			v.addf(field, "%q has an empty prefix", key)
+		case len(prefix) > maxLabelPrefixLength:
+			v.addf(field, "prefix of %q is longer than %d characters", key, maxLabelPrefixLength)
+		case !labelPrefixRegexp.MatchString(prefix):
+			v.addf(field, "prefix of %q must be a lowercase DNS subdomain", key)
+		}
+	}
+
+	switch {
+	case name == "":
+		v.addf(field, "%q has an empty name", key)
+	case len(name) > maxLabelNameLength:
+		v.addf(field, "name of %q is longer than %d characters", key, maxLabelNameLength)
+	case !labelNameRegexp.MatchString(name):
+		v.addf(field, "name of %q %s", key, labelSyntax)
+	}
+}
+
+// validateLabelValue checks a label or taint value, which may be empty
+func validateLabelValue(v *validation, field, value string) {
+	switch {
+	case value == "":
+	case len(value) > maxLabelNameLength:
+		v.addf(field, "%q is longer than %d characters", value, maxLabelNameLength)
+	case !labelNameRegexp.MatchString(value):
+		v.addf(field, "%q %s", value, labelSyntax)
+	}
+}
+
+func validateTaint(v *validation, prefix string, t *Taint) {
+	validateLabelKey(v, prefix+"key", t.Key)
+	validateLabelValue(v, prefix+"value", t.Value)
+
+	switch t.Effect {
+	case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+	case "":
+		v.addf(prefix+"effect", "is required")
+	default:
+		v.addf(prefix+"effect", "%q must be one of %s, %s or %s",
+			t.Effect, TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute)
+	}
+}
+
+// validateNodePoolLabels checks every key and value of a node pool label map
+func validateNodePoolLabels(v *validation, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		validateLabelKey(v, fmt.Sprintf("labels[%s].key", key), key)
+		validateLabelValue(v, fmt.Sprintf("labels[%s].value", key), labels[key])
+	}
+}
+
+// validateNodePoolTaints checks every taint and rejects two taints with the same key
+// and effect, which Kubernetes treats as the same taint
+func validateNodePoolTaints(v *validation, taints []Taint) {
+	seen := make(map[string]bool, len(taints))
+	for idx := range taints {
+		prefix := fmt.Sprintf("taints[%d].", idx)
+		validateTaint(v, prefix, &taints[idx])
+
+		id := taints[idx].Key + ":" + taints[idx].Effect
+		if seen[id] {
+			v.addf(prefix+"key", "duplicate taint %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+// NodePoolLabelSyncPlan lists the changes needed to converge the labels of a node pool
+type NodePoolLabelSyncPlan struct {
+	NodePoolID string
+	Creates    []NodePoolLabelReq
+	Deletes    []NodePoolLabel
+	Unchanged  []NodePoolLabel
+}
+
+// Empty reports whether the node pool already has the desired labels
+func (n *NodePoolLabelSyncPlan) Empty() bool {
+	return len(n.Creates) == 0 && len(n.Deletes) == 0
+}
+
+// String renders the plan for people to review
+func (n *NodePoolLabelSyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for node pool %s labels: %d to create, %d to delete, %d unchanged\n",
+		n.NodePoolID, len(n.Creates), len(n.Deletes), len(n.Unchanged))
+
+	for idx := range n.Creates {
+		fmt.Fprintf(&b, "  + %s=%s\n", n.Creates[idx].Key, n.Creates[idx].Value)
+	}
+	for idx := range n.Deletes {
+		fmt.Fprintf(&b, "  - %s=%s (label %s)\n", n.Deletes[idx].Key, n.Deletes[idx].Value, n.Deletes[idx].ID)
+	}
+
+	return b.String()
+}
+
+// NodePoolTaintSyncPlan lists the changes needed to converge the taints of a node pool
+type NodePoolTaintSyncPlan struct {
+	NodePoolID string
+	Creates    []NodePoolTaintReq
+	Deletes    []NodePoolTaint
+	Unchanged  []NodePoolTaint
+}
+
+// Empty reports whether the node pool already has the desired taints
+func (n *NodePoolTaintSyncPlan) Empty() bool {
+	return len(n.Creates) == 0 && len(n.Deletes) == 0
+}
+
+// String renders the plan for people to review
+func (n *NodePoolTaintSyncPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for node pool %s taints: %d to create, %d to delete, %d unchanged\n",
+		n.NodePoolID, len(n.Creates), len(n.Deletes), len(n.Unchanged))
+
+	for idx := range n.Creates {
+		fmt.Fprintf(&b, "  + %s\n", describeTaint(n.Creates[idx].Key, n.Creates[idx].Value, n.Creates[idx].Effect))
+	}
+	for idx := range n.Deletes {
+		t := &n.Deletes[idx]
+		fmt.Fprintf(&b, "  - %s (taint %s)\n", describeTaint(t.Key, t.Value, t.Effect), t.ID)
+	}
+
+	return b.String()
+}
+
+func describeTaint(key, value, effect string) string {
+	if value == "" {
+		return key + ":" + effect
+	}
+	return key + "=" + value + ":" + effect
+}
+
+// SyncNodePoolLabels converges the labels of a node pool to desired. Labels whose value
+// changed are deleted and created again as the API has no update call. Deletes are
+// applied before creates so a changed label never exists twice.
+func (k *KubernetesHandler) SyncNodePoolLabels(ctx context.Context, vkeID, nodePoolID string, desired map[string]string) (*NodePoolLabelSyncPlan, error) { //nolint:lll
+	v := newValidation("NodePoolLabels")
+	validateNodePoolLabels(v, desired)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	current, _, err := k.ListNodePoolLabels(ctx, vkeID, nodePoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planNodePoolLabelSync(nodePoolID, current, desired)
+
+	for idx := range plan.Deletes {
+		if err := k.DeleteNodePoolLabel(ctx, vkeID, nodePoolID, plan.Deletes[idx].ID); err != nil {
+			return plan, fmt.Errorf("deleting label %s: %w", plan.Deletes[idx].Key, err)
+		}
+	}
+	for idx := range plan.Creates {
+		if _, _, err := k.CreateNodePoolLabel(ctx, vkeID, nodePoolID, &plan.Creates[idx]); err != nil {
+			return plan, fmt.Errorf("creating label %s: %w", plan.Creates[idx].Key, err)
+		}
+	}
+
+	return plan, nil
+}
+
+func planNodePoolLabelSync(nodePoolID string, current []NodePoolLabel, desired map[string]string) *NodePoolLabelSyncPlan {
+	plan := &NodePoolLabelSyncPlan{NodePoolID: nodePoolID}
+
+	kept := make(map[string]bool, len(current))
+	for idx := range current {
+		label := current[idx]
+		if value, ok := desired[label.Key]; ok && value == label.Value && !kept[label.Key] {
+			kept[label.Key] = true
+			plan.Unchanged = append(plan.Unchanged, label)
+			continue
+		}
+		plan.Deletes = append(plan.Deletes, label)
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		if !kept[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		plan.Creates = append(plan.Creates, NodePoolLabelReq{Key: key, Value: desired[key]})
+	}
+
+	return plan
+}
+
+// SyncNodePoolTaints converges the taints of a node pool to desired. As in Kubernetes a
+// taint is identified by its key and effect, so two desired taints may not share both.
+// Taints whose value changed are deleted and created again, deletes first.
+func (k *KubernetesHandler) SyncNodePoolTaints(ctx context.Context, vkeID, nodePoolID string, desired []Taint) (*NodePoolTaintSyncPlan, error) { //nolint:lll
+	v := newValidation("NodePoolTaints")
+	validateNodePoolTaints(v, desired)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	current, _, err := k.ListNodePoolTaints(ctx, vkeID, nodePoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planNodePoolTaintSync(nodePoolID, current, desired)
+
+	for idx := range plan.Deletes {
+		if err := k.DeleteNodePoolTaint(ctx, vkeID, nodePoolID, plan.Deletes[idx].ID); err != nil {
+			return plan, fmt.Errorf("deleting taint %s: %w", plan.Deletes[idx].Key, err)
+		}
+	}
+	for idx := range plan.Creates {
+		if _, _, err := k.CreateNodePoolTaint(ctx, vkeID, nodePoolID, &plan.Creates[idx]); err != nil {
+			return plan, fmt.Errorf("creating taint %s: %w", plan.Creates[idx].Key, err)
+		}
+	}
+
+	return plan, nil
+}
+
+func planNodePoolTaintSync(nodePoolID string, current []NodePoolTaint, desired []Taint) *NodePoolTaintSyncPlan {
+	plan := &NodePoolTaintSyncPlan{NodePoolID: nodePoolID}
+
+	wanted := make(map[Taint]bool, len(desired))
+	for _, t := range desired {
+		wanted[t] = false
+	}
+
+	for idx := range current {
+		key := Taint{Key: current[idx].Key, Value: current[idx].Value, Effect: current[idx].Effect}
+		if matched, ok := wanted[key]; ok && !matched {
+			wanted[key] = true
+			plan.Unchanged = append(plan.Unchanged, current[idx])
+			continue
+		}
+		plan.Deletes = append(plan.Deletes, current[idx])
+	}
+
+	for _, t := range desired {
+		if !wanted[t] {
+			plan.Creates = append(plan.Creates, NodePoolTaintReq{Key: t.Key, Value: t.Value, Effect: t.Effect})
+		}
+	}
+
+	return plan
+}
@@ -0,0 +1,172 @@
+package govultr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNodePoolLabelReq_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    NodePoolLabelReq
+		fields []string
+	}{
+		{name: "plain", req: NodePoolLabelReq{Key: "tier", Value: "web"}},
+		{name: "prefixed", req: NodePoolLabelReq{Key: "node.kubernetes.io/instance-type", Value: "vc2_4c-8gb"}},
+		{name: "empty value", req: NodePoolLabelReq{Key: "gpu"}},
+		{name: "missing key", req: NodePoolLabelReq{Value: "web"}, fields: []string{"key"}},
+		{name: "uppercase prefix", req: NodePoolLabelReq{Key: "Example.com/tier", Value: "web"}, fields: []string{"key"}},
+		{name: "empty name", req: NodePoolLabelReq{Key: "example.com/", Value: "web"}, fields: []string{"key"}},
+		{name: "bad name", req: NodePoolLabelReq{Key: "-tier", Value: "web"}, fields: []string{"key"}},
+		{name: "long name", req: NodePoolLabelReq{Key: strings.Repeat("a", 64)}, fields: []string{"key"}},
+		{name: "bad value", req: NodePoolLabelReq{Key: "tier", Value: "web tier"}, fields: []string{"value"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.req.Validate(), tt.fields)
+		})
+	}
+}
+
+func TestNodePoolTaintReq_Validate(t *testing.T) {
+	assertValidationFields(t, (&NodePoolTaintReq{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute}).Validate(), nil)
+	assertValidationFields(t, (&NodePoolTaintReq{Key: "dedicated", Effect: "NoRun"}).Validate(), []string{"effect"})
+	assertValidationFields(t, (&NodePoolTaintReq{Value: "_gpu"}).Validate(), []string{"key", "value", "effect"})
+}
+
+// fakeNodePoolMetadata serves the label or taint endpoints of one node pool from memory
+type fakeNodePoolMetadata struct {
+	items   map[string]map[string]string
+	nextID  int
+	deleted []string
+	created []string
+}
+
+func (f *fakeNodePoolMetadata) handle(kind string) {
+	base := "/v2/kubernetes/clusters/abc/node-pools/pool/" + kind
+	mux.HandleFunc(base, func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			ids := make([]string, 0, len(f.items))
+			for id := range f.items {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			list := make([]map[string]string, 0, len(ids))
+			for _, id := range ids {
+				list = append(list, f.items[id])
+			}
+			_ = json.NewEncoder(writer).Encode(map[string]interface{}{kind: list})
+		case http.MethodPost:
+			item := map[string]string{}
+			_ = json.NewDecoder(request.Body).Decode(&item)
+			f.nextID++
+			item["id"] = fmt.Sprintf("new-%d", f.nextID)
+			f.items[item["id"]] = item
+			f.created = append(f.created, item["key"]+"="+item["value"]+":"+item["effect"])
+			_ = json.NewEncoder(writer).Encode(map[string]interface{}{strings.TrimSuffix(kind, "s"): item})
+		default:
+			http.Error(writer, "unexpected method "+request.Method, http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc(base+"/", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			http.Error(writer, "unexpected method "+request.Method, http.StatusMethodNotAllowed)
+			return
+		}
+		id := strings.TrimPrefix(request.URL.Path, base+"/")
+		f.deleted = append(f.deleted, id)
+		delete(f.items, id)
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func TestKubernetesHandler_SyncNodePoolLabels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := &fakeNodePoolMetadata{items: map[string]map[string]string{
+		"l1": {"id": "l1", "key": "tier", "value": "web"},
+		"l2": {"id": "l2", "key": "team", "value": "payments"},
+		"l3": {"id": "l3", "key": "legacy", "value": "true"},
+	}}
+	fake.handle("labels")
+
+	desired := map[string]string{"tier": "web", "team": "billing", "example.com/owner": "ops"}
+	plan, err := client.Kubernetes.SyncNodePoolLabels(ctx, "abc", "pool", desired)
+	if err != nil {
+		t.Fatalf("Kubernetes.SyncNodePoolLabels returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(fake.deleted, []string{"l2", "l3"}) {
+		t.Errorf("Kubernetes.SyncNodePoolLabels deleted %v, expected [l2 l3]", fake.deleted)
+	}
+	if !reflect.DeepEqual(fake.created, []string{"example.com/owner=ops:", "team=billing:"}) {
+		t.Errorf("Kubernetes.SyncNodePoolLabels created %v", fake.created)
+	}
+	if len(plan.Unchanged) != 1 || plan.Unchanged[0].ID != "l1" {
+		t.Errorf("Kubernetes.SyncNodePoolLabels unchanged = %+v", plan.Unchanged)
+	}
+
+	expected := "Plan for node pool pool labels: 2 to create, 2 to delete, 1 unchanged\n" +
+		"  + example.com/owner=ops\n  + team=billing\n  - team=payments (label l2)\n  - legacy=true (label l3)\n"
+	if plan.String() != expected {
+		t.Errorf("NodePoolLabelSyncPlan.String returned\n%s\nexpected\n%s", plan, expected)
+	}
+
+	plan, err = client.Kubernetes.SyncNodePoolLabels(ctx, "abc", "pool", desired)
+	if err != nil || !plan.Empty() {
+		t.Errorf("Kubernetes.SyncNodePoolLabels second run returned %+v, %v", plan, err)
+	}
+
+	if _, err := client.Kubernetes.SyncNodePoolLabels(ctx, "abc", "pool", map[string]string{"tier": "-web"}); err == nil {
+		t.Error("Kubernetes.SyncNodePoolLabels expected a validation error")
+	}
+	if len(fake.deleted) != 2 {
+		t.Errorf("Kubernetes.SyncNodePoolLabels changed labels despite invalid input: %v", fake.deleted)
+	}
+}
+
+func TestKubernetesHandler_SyncNodePoolTaints(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := &fakeNodePoolMetadata{items: map[string]map[string]string{
+		"t1": {"id": "t1", "key": "dedicated", "value": "gpu", "effect": TaintEffectNoSchedule},
+		"t2": {"id": "t2", "key": "dedicated", "value": "gpu", "effect": TaintEffectNoExecute},
+		"t3": {"id": "t3", "key": "spot", "value": "", "effect": TaintEffectPreferNoSchedule},
+	}}
+	fake.handle("taints")
+
+	desired := []Taint{
+		{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "ml", Effect: TaintEffectNoExecute},
+	}
+	plan, err := client.Kubernetes.SyncNodePoolTaints(ctx, "abc", "pool", desired)
+	if err != nil {
+		t.Fatalf("Kubernetes.SyncNodePoolTaints returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(fake.deleted, []string{"t2", "t3"}) {
+		t.Errorf("Kubernetes.SyncNodePoolTaints deleted %v, expected [t2 t3]", fake.deleted)
+	}
+	if !reflect.DeepEqual(fake.created, []string{"dedicated=ml:NoExecute"}) {
+		t.Errorf("Kubernetes.SyncNodePoolTaints created %v", fake.created)
+	}
+	if !strings.Contains(plan.String(), "  - spot:PreferNoSchedule (taint t3)\n") {
+		t.Errorf("NodePoolTaintSyncPlan.String returned\n%s", plan)
+	}
+
+	_, err = client.Kubernetes.SyncNodePoolTaints(ctx, "abc", "pool", []Taint{
+		{Key: "dedicated", Effect: TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+	})
+	assertValidationFields(t, err, []string{"taints[1].key"})
+}
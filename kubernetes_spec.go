@@ -0,0 +1,214 @@
+package govultr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ClusterSpec is a portable description of a VKE cluster. It embeds ClusterReq, so an
+// exported document can be passed to CreateCluster as is, and adds the rules of the
+// cluster firewall group, which the create call has no room for.
+type ClusterSpec struct {
+	ClusterReq
+	Firewall *FirewallPolicy `json:"firewall,omitempty"`
+}
+
+// ClusterCloneOptions overrides parts of a ClusterSpec when cloning it. Empty fields
+// keep the value from the spec.
+type ClusterCloneOptions struct {
+	Label   string
+	Region  string
+	Version string
+	// Wait controls polling for the new cluster's firewall group when the create
+	// response does not include it yet
+	Wait *WaitOptions
+}
+
+// ExportClusterSpec reads a cluster, its node pools with their labels and taints and
+// the rules of its firewall group into a ClusterSpec
+func (k *KubernetesHandler) ExportClusterSpec(ctx context.Context, vkeID string) (*ClusterSpec, error) {
+	cluster, _, err := k.GetCluster(ctx, vkeID)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := listAll(func(options *ListOptions) ([]NodePool, *Meta, *http.Response, error) {
+		return k.ListNodePools(ctx, vkeID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ClusterSpec{ClusterReq: ClusterReq{
+		Label:           cluster.Label,
+		Region:          cluster.Region,
+		Version:         cluster.Version,
+		HAControlPlanes: cluster.HAControlPlanes,
+		EnableFirewall:  cluster.FirewallGroupID != "",
+		NodePools:       make([]NodePoolReq, 0, len(pools)),
+	}}
+
+	if cluster.OIDCConfig != (ClusterOIDCConfig{}) {
+		oidc := cluster.OIDCConfig
+		spec.OIDCConfig = &oidc
+	}
+
+	for idx := range pools {
+		pool, err := k.exportNodePool(ctx, vkeID, &pools[idx])
+		if err != nil {
+			return nil, err
+		}
+		spec.NodePools = append(spec.NodePools, *pool)
+	}
+
+	if cluster.FirewallGroupID != "" {
+		if spec.Firewall, err = k.client.FirewallGroup.ExportPolicy(ctx, cluster.FirewallGroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return spec, nil
+}
+
+func (k *KubernetesHandler) exportNodePool(ctx context.Context, vkeID string, pool *NodePool) (*NodePoolReq, error) {
+	req := &NodePoolReq{
+		NodeQuantity: pool.NodeQuantity,
+		Label:        pool.Label,
+		Plan:         pool.Plan,
+		Tag:          pool.Tag,
+		MinNodes:     pool.MinNodes,
+		MaxNodes:     pool.MaxNodes,
+		AutoScaler:   BoolToBoolPtr(pool.AutoScaler),
+		UserData:     pool.UserData,
+	}
+	if pool.VPCOnly {
+		req.VPCOnly = BoolToBoolPtr(true)
+	}
+
+	labels, _, err := k.ListNodePoolLabels(ctx, vkeID, pool.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) > 0 {
+		req.Labels = make(map[string]string, len(labels))
+		for _, label := range labels {
+			req.Labels[label.Key] = label.Value
+		}
+	}
+
+	taints, _, err := k.ListNodePoolTaints(ctx, vkeID, pool.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, taint := range taints {
+		req.Taints = append(req.Taints, Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+	}
+
+	return req, nil
+}
+
+// CloneCluster creates a new cluster from spec after applying the overrides in opts.
+// When the spec carries firewall rules the ones missing from the firewall group of
+// the new cluster are created. The rules VKE generated for the new cluster are kept,
+// and spec rules with the same notes as one of them are skipped as the generated
+// rules of the source cluster.
+func (k *KubernetesHandler) CloneCluster(ctx context.Context, spec *ClusterSpec, opts *ClusterCloneOptions) (*Cluster, error) {
+	if opts == nil {
+		opts = &ClusterCloneOptions{}
+	}
+
+	req := spec.ClusterReq
+	req.NodePools = append([]NodePoolReq(nil), spec.NodePools...)
+	if opts.Label != "" {
+		req.Label = opts.Label
+	}
+	if opts.Region != "" {
+		req.Region = opts.Region
+	}
+	if opts.Version != "" {
+		req.Version = opts.Version
+	}
+	if spec.Firewall != nil {
+		req.EnableFirewall = true
+	}
+
+	cluster, _, err := k.CreateCluster(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Firewall == nil {
+		return cluster, nil
+	}
+
+	err = waitFor(ctx, opts.Wait, fmt.Sprintf("cluster %s firewall group", cluster.ID), func(ctx context.Context) (bool, error) {
+		if cluster.FirewallGroupID != "" {
+			return true, nil
+		}
+		current, _, err := k.GetCluster(ctx, cluster.ID)
+		if err != nil {
+			return false, err
+		}
+		cluster = current
+		return cluster.FirewallGroupID != "", nil
+	})
+	if err != nil {
+		return cluster, err
+	}
+
+	if err := k.cloneFirewallRules(ctx, cluster.FirewallGroupID, spec.Firewall.Rules); err != nil {
+		return cluster, fmt.Errorf("syncing firewall rules of cluster %s: %w", cluster.ID, err)
+	}
+
+	return cluster, nil
+}
+
+// cloneFirewallRules adds the user defined rules to a new cluster firewall group
+// without touching the rules VKE created in it
+func (k *KubernetesHandler) cloneFirewallRules(ctx context.Context, fwGroupID string, rules []FirewallRuleReq) error {
+	generated, err := listAll(func(options *ListOptions) ([]FirewallRule, *Meta, *http.Response, error) {
+		return k.client.FirewallRule.List(ctx, fwGroupID, options)
+	})
+	if err != nil {
+		return err
+	}
+
+	desired := make([]FirewallRuleReq, 0, len(generated)+len(rules))
+	notes := make(map[string]bool, len(generated))
+	for idx := range generated {
+		desired = append(desired, firewallRuleToReq(&generated[idx]))
+		if generated[idx].Notes != "" {
+			notes[generated[idx].Notes] = true
+		}
+	}
+	for idx := range rules {
+		if !notes[rules[idx].Notes] {
+			desired = append(desired, rules[idx])
+		}
+	}
+
+	_, err = k.client.FirewallRule.SyncFirewallRules(ctx, fwGroupID, desired)
+	return err
+}
+
+// ReadClusterSpec reads a cluster spec document in JSON or YAML format
+func ReadClusterSpec(r io.Reader) (*ClusterSpec, error) {
+	spec := &ClusterSpec{}
+	if err := readDocument(r, spec); err != nil {
+		return nil, fmt.Errorf("reading cluster spec: %w", err)
+	}
+	return spec, nil
+}
+
+// JSON returns the spec as an indented JSON document
+func (c *ClusterSpec) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// YAML returns the spec as a YAML document
+func (c *ClusterSpec) YAML() ([]byte, error) {
+	return marshalYAML(c)
+}
@@ -0,0 +1,226 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testClusterSpec = &ClusterSpec{
+	ClusterReq: ClusterReq{
+		Label:           "production",
+		Region:          "ewr",
+		Version:         "v1.29.4+1",
+		HAControlPlanes: true,
+		EnableFirewall:  true,
+		OIDCConfig:      &ClusterOIDCConfig{IssuerURL: "https://issuer.example.com", ClientID: "vke"},
+		NodePools: []NodePoolReq{
+			{
+				NodeQuantity: 3,
+				Label:        "web",
+				Plan:         "vc2-2c-4gb",
+				Tag:          "frontend",
+				MinNodes:     2,
+				MaxNodes:     5,
+				AutoScaler:   BoolToBoolPtr(true),
+				Labels:       map[string]string{"tier": "web", "example.com/team": "storefront"},
+				Taints:       []Taint{{Key: "dedicated", Value: "web", Effect: TaintEffectNoSchedule}},
+				UserData:     "I2Nsb3VkLWNvbmZpZw==",
+			},
+			{
+				NodeQuantity: 1,
+				Label:        "batch",
+				Plan:         "vc2-4c-8gb",
+				AutoScaler:   BoolToBoolPtr(false),
+				VPCOnly:      BoolToBoolPtr(true),
+			},
+		},
+	},
+	Firewall: &FirewallPolicy{
+		Version:     firewallPolicyVersion,
+		Description: "vke production",
+		Rules: []FirewallRuleReq{
+			{IPType: "v4", Protocol: "tcp", Port: "30000:32767", Subnet: "0.0.0.0", SubnetSize: 0},
+		},
+	},
+}
+
+func TestKubernetesHandler_ExportClusterSpec(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/abc", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"abc","label":"production","region":"ewr","version":"v1.29.4+1",
+			"ha_controlplanes":true,"firewall_group_id":"fw","oidc":{"issuer_url":"https://issuer.example.com","client_id":"vke"}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"node_pools":[
+			{"id":"p1","label":"web","plan":"vc2-2c-4gb","tag":"frontend","node_quantity":3,"min_nodes":2,"max_nodes":5,
+				"auto_scaler":true,"user_data":"I2Nsb3VkLWNvbmZpZw=="},
+			{"id":"p2","label":"batch","plan":"vc2-4c-8gb","node_quantity":1,"vpc_only":true}
+		],"meta":{"total":2,"links":{}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools/p1/labels", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"labels":[{"id":"l1","key":"tier","value":"web"},{"id":"l2","key":"example.com/team","value":"storefront"}]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools/p1/taints", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"taints":[{"id":"t1","key":"dedicated","value":"web","effect":"NoSchedule"}]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools/p2/labels", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"labels":[]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools/p2/taints", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"taints":[]}`)
+	})
+	mux.HandleFunc("/v2/firewalls/fw", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_group":{"id":"fw","description":"vke production"}}`)
+	})
+	mux.HandleFunc("/v2/firewalls/fw/rules", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"firewall_rules":[
+			{"id":1,"ip_type":"v4","action":"accept","protocol":"tcp","port":"30000:32767","subnet":"0.0.0.0","subnet_size":0}
+		],"meta":{"total":1,"links":{}}}`)
+	})
+
+	spec, err := client.Kubernetes.ExportClusterSpec(ctx, "abc")
+	if err != nil {
+		t.Fatalf("Kubernetes.ExportClusterSpec returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(spec, testClusterSpec) {
+		t.Errorf("Kubernetes.ExportClusterSpec returned %+v, expected %+v", spec, testClusterSpec)
+	}
+}
+
+func TestClusterSpec_RoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			var data []byte
+			var err error
+			if format == "json" {
+				data, err = testClusterSpec.JSON()
+			} else {
+				data, err = testClusterSpec.YAML()
+			}
+			if err != nil {
+				t.Fatalf("ClusterSpec.%s returned %+v", format, err)
+			}
+
+			spec, err := ReadClusterSpec(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ReadClusterSpec returned %+v for\n%s", err, data)
+			}
+
+			if !reflect.DeepEqual(spec, testClusterSpec) {
+				t.Errorf("ReadClusterSpec returned %+v, expected %+v", spec, testClusterSpec)
+			}
+		})
+	}
+
+	// the spec is a ClusterReq document with an extra firewall section
+	data, _ := testClusterSpec.JSON()
+	var req ClusterReq
+	if err := json.Unmarshal(data, &req); err != nil || !reflect.DeepEqual(req, testClusterSpec.ClusterReq) {
+		t.Errorf("ClusterSpec JSON decoded into ClusterReq as %+v (%v)", req, err)
+	}
+}
+
+func TestKubernetesHandler_CloneCluster(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var created ClusterReq
+	mux.HandleFunc("/v2/kubernetes/clusters", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			t.Errorf("Request method = %v, expecting %v", request.Method, http.MethodPost)
+		}
+		if err := json.NewDecoder(request.Body).Decode(&created); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"dr","label":"production-dr","region":"ams","status":"pending"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/dr", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"dr","label":"production-dr","region":"ams","firewall_group_id":"fw-dr"}}`)
+	})
+
+	var rules []FirewallRuleReq
+	mux.HandleFunc("/v2/firewalls/fw-dr/rules", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, `{"firewall_rules":[],"meta":{"total":0,"links":{}}}`)
+			return
+		}
+		var rule FirewallRuleReq
+		if err := json.NewDecoder(request.Body).Decode(&rule); err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+		fmt.Fprintf(writer, `{"firewall_rule":{"id":%d}}`, len(rules))
+	})
+
+	cluster, err := client.Kubernetes.CloneCluster(ctx, testClusterSpec, &ClusterCloneOptions{Label: "production-dr", Region: "ams"})
+	if err != nil {
+		t.Fatalf("Kubernetes.CloneCluster returned %+v", err)
+	}
+
+	expected := testClusterSpec.ClusterReq
+	expected.Label, expected.Region = "production-dr", "ams"
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("Kubernetes.CloneCluster sent %+v, expected %+v", created, expected)
+	}
+
+	if cluster.FirewallGroupID != "fw-dr" || !reflect.DeepEqual(rules, testClusterSpec.Firewall.Rules) {
+		t.Errorf("Kubernetes.CloneCluster synced rules %+v to %+v", rules, cluster)
+	}
+
+	if testClusterSpec.Label != "production" || testClusterSpec.Region != "ewr" {
+		t.Errorf("Kubernetes.CloneCluster modified the spec: %+v", testClusterSpec)
+	}
+}
+
+func TestKubernetesHandler_CloneClusterGeneratedRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"vke_cluster":{"id":"dr","firewall_group_id":"fw-dr"}}`)
+	})
+
+	var rules []FirewallRuleReq
+	mux.HandleFunc("/v2/firewalls/fw-dr/rules", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			fmt.Fprint(writer, `{"firewall_rules":[
+				{"id":1,"ip_type":"v4","protocol":"tcp","port":"6443","subnet":"10.2.0.0","subnet_size":16,"notes":"vke control plane"},
+				{"id":2,"ip_type":"v4","protocol":"tcp","port":"30000:32767","subnet":"0.0.0.0","subnet_size":0}
+			],"meta":{"total":2,"links":{}}}`)
+			return
+		}
+		var rule FirewallRuleReq
+		if err := json.NewDecoder(request.Body).Decode(&rule); err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+		fmt.Fprintf(writer, `{"firewall_rule":{"id":%d}}`, len(rules)+2)
+	})
+	mux.HandleFunc("/v2/firewalls/fw-dr/rules/", func(writer http.ResponseWriter, request *http.Request) {
+		t.Errorf("Kubernetes.CloneCluster deleted %s", request.URL.Path)
+	})
+
+	admin := FirewallRuleReq{IPType: "v4", Protocol: "tcp", Port: "22", Subnet: "192.0.2.0", SubnetSize: 24, Notes: "admin"}
+	spec := *testClusterSpec
+	spec.Firewall = &FirewallPolicy{Version: firewallPolicyVersion, Rules: []FirewallRuleReq{
+		// generated for the source cluster
+		{IPType: "v4", Protocol: "tcp", Port: "6443", Subnet: "10.1.0.0", SubnetSize: 16, Notes: "vke control plane"},
+		{IPType: "v4", Protocol: "tcp", Port: "30000-32767", Subnet: "0.0.0.0", SubnetSize: 0},
+		admin,
+	}}
+
+	if _, err := client.Kubernetes.CloneCluster(ctx, &spec, nil); err != nil {
+		t.Fatalf("Kubernetes.CloneCluster returned %+v", err)
+	}
+
+	if !reflect.DeepEqual(rules, []FirewallRuleReq{admin}) {
+		t.Errorf("Kubernetes.CloneCluster created %+v, expected only %+v", rules, admin)
+	}
+}
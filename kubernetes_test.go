@@ -930,3 +930,48 @@ func TestTaintStructForNodePool(t *testing.T) {
 		t.Errorf("Expected 2 taints in the NodePoolReqUpdate struct, got %d", len(updateReq.Taints))
 	}
 }
+
+func TestClusterReq_Validate(t *testing.T) {
+	autoScaler := BoolToBoolPtr(true)
+	tests := []struct {
+		name   string
+		req    ClusterReq
+		fields []string
+	}{
+		{
+			name: "valid",
+			req: ClusterReq{Region: "ewr", Version: "v1.31.0+1", NodePools: []NodePoolReq{
+				{Label: "pool", Plan: "vc2-2c-4gb", NodeQuantity: 2, AutoScaler: autoScaler, MinNodes: 1, MaxNodes: 3},
+			}},
+		},
+		{name: "no node pools", req: ClusterReq{Region: "ewr", Version: "v1.31.0+1"}, fields: []string{"node_pools"}},
+		{
+			name: "invalid node pool",
+			req: ClusterReq{Version: "v1.31.0+1", NodePools: []NodePoolReq{
+				{Label: "pool", Plan: "vc2-2c-4gb", NodeQuantity: 5, AutoScaler: autoScaler, MinNodes: 1, MaxNodes: 3},
+				{Plan: "vc2-2c-4gb", NodeQuantity: 1, MinNodes: 3, MaxNodes: 2},
+			}},
+			fields: []string{"region", "node_pools[0].node_quantity", "node_pools[1].label", "node_pools[1].min_nodes"},
+		},
+		{
+			name: "invalid labels and taints",
+			req: ClusterReq{Region: "ewr", Version: "v1.31.0+1", NodePools: []NodePoolReq{
+				{
+					Label: "pool", Plan: "vc2-2c-4gb", NodeQuantity: 1,
+					Labels: map[string]string{"app.kubernetes.io/tier": "web", "bad key": "x"},
+					Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}, {Key: "dedicated", Effect: "NoSchedule"}},
+				},
+			}},
+			fields: []string{"node_pools[0].labels[bad key].key", "node_pools[0].taints[1].key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationFields(t, tt.req.Validate(), tt.fields)
+		})
+	}
+
+	update := &NodePoolReqUpdate{AutoScaler: autoScaler, MinNodes: 0, MaxNodes: 4}
+	assertValidationFields(t, update.Validate(), []string{"min_nodes"})
+}
@@ -0,0 +1,479 @@
+package govultr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoClusterUpgrade is returned when a VKE cluster has no version it can be upgraded to
+var ErrNoClusterUpgrade = errors.New("no kubernetes version upgrade is available for the cluster")
+
+// ClusterUpgradePhase identifies the part of the cluster a ClusterUpgradeStep acts on
+type ClusterUpgradePhase string
+
+// Phases of an orchestrated cluster upgrade
+const (
+	ClusterUpgradePhaseControlPlane ClusterUpgradePhase = "control-plane"
+	ClusterUpgradePhaseNode         ClusterUpgradePhase = "node"
+)
+
+// ClusterUpgradeStep records a single step of an orchestrated cluster upgrade. Index is
+// one based and Total is the number of steps planned for the run.
+type ClusterUpgradeStep struct {
+	Phase      ClusterUpgradePhase
+	Index      int
+	Total      int
+	Version    string
+	NodePoolID string
+	NodeID     string
+	Started    time.Time
+	Finished   time.Time
+	Skipped    bool
+	Err        error
+}
+
+// String describes the step for logs and progress output
+func (s *ClusterUpgradeStep) String() string {
+	var target string
+	switch s.Phase {
+	case ClusterUpgradePhaseControlPlane:
+		target = "upgrade control plane to " + s.Version
+	case ClusterUpgradePhaseNode:
+		target = fmt.Sprintf("recycle node %s in pool %s", s.NodeID, s.NodePoolID)
+	}
+
+	state := "started"
+	switch {
+	case s.Err != nil:
+		state = "failed: " + s.Err.Error()
+	case s.Skipped:
+		state = "skipped"
+	case !s.Finished.IsZero():
+		state = "done in " + s.Finished.Sub(s.Started).Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("[%d/%d] %s: %s", s.Index, s.Total, target, state)
+}
+
+// ClusterUpgradeReport is the outcome of ClusterUpgrader.Run
+type ClusterUpgradeReport struct {
+	ClusterID   string
+	FromVersion string
+	ToVersion   string
+	Steps       []ClusterUpgradeStep
+}
+
+// String renders the report one step per line
+func (r *ClusterUpgradeReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cluster %s: %s -> %s\n", r.ClusterID, r.FromVersion, r.ToVersion)
+	for idx := range r.Steps {
+		b.WriteString(r.Steps[idx].String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// ClusterUpgrader upgrades a VKE cluster end to end. It upgrades the control plane to
+// Version, waits for the cluster to settle and then rolls every node pool by recycling
+// its nodes one at a time, waiting for each replacement to become active.
+//
+// Progress is kept between calls to Run, so a run that failed or was cancelled can be
+// resumed by calling Run again and continues with the first unfinished step. The nodes
+// to recycle are the ones each pool had when the first run started; their replacements
+// are never recycled again. Pause stops the upgrade before the next step starts until
+// Resume is called.
+//
+// Run must not be called concurrently with itself. Pause, Resume and Paused are safe to
+// call from other goroutines while Run is in progress.
+type ClusterUpgrader struct {
+	client *Client
+
+	ClusterID string
+	// Version is the target kubernetes version. When empty, Run picks the next
+	// allowed version with NextClusterVersion.
+	Version string
+	Wait    *WaitOptions
+	// OnProgress is called when a step starts and again when it finishes
+	OnProgress func(step ClusterUpgradeStep)
+
+	mu           sync.Mutex
+	resume       chan struct{}
+	fromVersion  string
+	controlPlane bool
+	// original holds the nodes of each pool before the upgrade, by pool ID
+	original map[string][]Node
+	recycled map[string]bool
+	steps    []ClusterUpgradeStep
+}
+
+// NewClusterUpgrader returns a ClusterUpgrader for the cluster
+func NewClusterUpgrader(client *Client, clusterID string) *ClusterUpgrader {
+	return &ClusterUpgrader{
+		client:    client,
+		ClusterID: clusterID,
+		recycled:  make(map[string]bool),
+	}
+}
+
+// Pause stops the upgrade before its next step. A step already in progress is finished first.
+func (u *ClusterUpgrader) Pause() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.resume == nil {
+		u.resume = make(chan struct{})
+	}
+}
+
+// Resume continues a paused upgrade
+func (u *ClusterUpgrader) Resume() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.resume != nil {
+		close(u.resume)
+		u.resume = nil
+	}
+}
+
+// Paused reports whether the upgrade is paused
+func (u *ClusterUpgrader) Paused() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.resume != nil
+}
+
+// checkpoint blocks while the upgrade is paused
+func (u *ClusterUpgrader) checkpoint(ctx context.Context) error {
+	u.mu.Lock()
+	resume := u.resume
+	u.mu.Unlock()
+
+	if resume == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run performs the upgrade, or resumes it from the first unfinished step. The returned
+// report lists every step taken across all runs, including a failed one.
+func (u *ClusterUpgrader) Run(ctx context.Context) (*ClusterUpgradeReport, error) {
+	k := u.client.Kubernetes
+
+	cluster, _, err := k.GetCluster(ctx, u.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.fromVersion == "" {
+		u.fromVersion = cluster.Version
+	}
+
+	if u.Version == "" {
+		if u.Version, err = u.nextVersion(ctx, cluster.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	pools, err := listAll(func(options *ListOptions) ([]NodePool, *Meta, *http.Response, error) {
+		return k.ListNodePools(ctx, u.ClusterID, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if u.original == nil {
+		u.original = make(map[string][]Node, len(pools))
+		for idx := range pools {
+			u.original[pools[idx].ID] = pools[idx].Nodes
+		}
+	}
+
+	total := 1
+	for _, nodes := range u.original {
+		total += len(nodes)
+	}
+
+	if !u.controlPlane {
+		err = u.step(ctx, ClusterUpgradeStep{Phase: ClusterUpgradePhaseControlPlane, Version: u.Version, Total: total},
+			func(ctx context.Context, step *ClusterUpgradeStep) error {
+				return u.upgradeControlPlane(ctx, cluster, step)
+			})
+		if err != nil {
+			return u.report(), err
+		}
+		u.controlPlane = true
+	}
+
+	if err := u.rollNodePools(ctx, pools, total); err != nil {
+		return u.report(), err
+	}
+
+	return u.report(), nil
+}
+
+func (u *ClusterUpgrader) nextVersion(ctx context.Context, current string) (string, error) {
+	upgrades, _, err := u.client.Kubernetes.GetUpgrades(ctx, u.ClusterID)
+	if err != nil {
+		return "", err
+	}
+
+	versions, _, err := u.client.Kubernetes.GetVersions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return NextClusterVersion(current, upgrades, versions.Versions)
+}
+
+func (u *ClusterUpgrader) report() *ClusterUpgradeReport {
+	return &ClusterUpgradeReport{
+		ClusterID:   u.ClusterID,
+		FromVersion: u.fromVersion,
+		ToVersion:   u.Version,
+		Steps:       append([]ClusterUpgradeStep(nil), u.steps...),
+	}
+}
+
+// step runs a single upgrade step once the upgrade is not paused and records its outcome
+func (u *ClusterUpgrader) step(ctx context.Context, step ClusterUpgradeStep, run func(context.Context, *ClusterUpgradeStep) error) error {
+	if err := u.checkpoint(ctx); err != nil {
+		return err
+	}
+
+	step.Index = len(u.steps) + 1
+	step.Started = time.Now()
+	u.progress(step)
+
+	step.Err = run(ctx, &step)
+	step.Finished = time.Now()
+	u.steps = append(u.steps, step)
+	u.progress(step)
+
+	return step.Err
+}
+
+func (u *ClusterUpgrader) progress(step ClusterUpgradeStep) {
+	if u.OnProgress != nil {
+		u.OnProgress(step)
+	}
+}
+
+func (u *ClusterUpgrader) upgradeControlPlane(ctx context.Context, cluster *Cluster, step *ClusterUpgradeStep) error {
+	k := u.client.Kubernetes
+
+	// A cluster already on the target version was upgraded by an earlier, interrupted run
+	if cluster.Version == u.Version {
+		step.Skipped = true
+	} else {
+		upgrades, _, err := k.GetUpgrades(ctx, u.ClusterID)
+		if err != nil {
+			return err
+		}
+		if !containsString(upgrades, u.Version) {
+			return fmt.Errorf("version %s is not an available upgrade for cluster %s", u.Version, u.ClusterID)
+		}
+
+		if err := k.Upgrade(ctx, u.ClusterID, &ClusterUpgradeReq{UpgradeVersion: u.Version}); err != nil {
+			return err
+		}
+	}
+
+	desc := fmt.Sprintf("cluster %s control plane to run %s", u.ClusterID, u.Version)
+	return waitFor(ctx, u.Wait, desc, func(ctx context.Context) (bool, error) {
+		cluster, _, err := k.GetCluster(ctx, u.ClusterID)
+		if err != nil {
+			return false, err
+		}
+
+		return cluster.Status == VKEStatusActive && cluster.Version == u.Version, nil
+	})
+}
+
+func (u *ClusterUpgrader) rollNodePools(ctx context.Context, pools []NodePool, total int) error {
+	for idx := range pools {
+		pool := &pools[idx]
+		for _, node := range u.original[pool.ID] {
+			if u.recycled[node.ID] {
+				continue
+			}
+
+			// a node missing from the pool was replaced by an interrupted run
+			if !containsNode(pool.Nodes, node.ID) {
+				u.recycled[node.ID] = true
+				continue
+			}
+
+			step := ClusterUpgradeStep{
+				Phase:      ClusterUpgradePhaseNode,
+				Total:      total,
+				Version:    u.Version,
+				NodePoolID: pool.ID,
+				NodeID:     node.ID,
+			}
+			err := u.step(ctx, step, func(ctx context.Context, _ *ClusterUpgradeStep) error {
+				return u.recycleNode(ctx, pool, &node)
+			})
+			if err != nil {
+				return err
+			}
+			u.recycled[node.ID] = true
+		}
+	}
+
+	return nil
+}
+
+func containsNode(nodes []Node, nodeID string) bool {
+	for idx := range nodes {
+		if nodes[idx].ID == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// recycleNode recycles one node and waits until the pool is back at its node quantity
+// with every node active. The recycled node counts as replaced once it has been seen
+// in a non active state, has gone from the pool or reports a newer creation date, so
+// the old node still being listed as active right after the call is not mistaken
+// for a finished recycle.
+func (u *ClusterUpgrader) recycleNode(ctx context.Context, pool *NodePool, node *Node) error {
+	k := u.client.Kubernetes
+	if err := k.RecycleNodePoolInstance(ctx, u.ClusterID, pool.ID, node.ID); err != nil {
+		return err
+	}
+
+	replaced := false
+	desc := fmt.Sprintf("node %s in pool %s to be recycled", node.ID, pool.ID)
+	return waitFor(ctx, u.Wait, desc, func(ctx context.Context) (bool, error) {
+		nodes, err := listAll(func(options *ListOptions) ([]Node, *Meta, *http.Response, error) {
+			return k.ListWorkerNodes(ctx, u.ClusterID, pool.ID, options)
+		})
+		if err != nil {
+			return false, err
+		}
+
+		found := false
+		ready := len(nodes) >= pool.NodeQuantity
+		for idx := range nodes {
+			if nodes[idx].ID == node.ID {
+				found = true
+				if nodes[idx].Status != VKEStatusActive || nodes[idx].DateCreated != node.DateCreated {
+					replaced = true
+				}
+			}
+			if nodes[idx].Status != VKEStatusActive {
+				ready = false
+			}
+		}
+
+		if !found {
+			replaced = true
+		}
+
+		return replaced && ready, nil
+	})
+}
+
+// NextClusterVersion picks the version a cluster on current should be upgraded to. Only
+// versions in upgrades that are newer than current are considered and, when versions is
+// not empty, they must also be listed there. The lowest newer minor release is chosen so
+// that minor versions are never skipped, taking its latest patch release.
+func NextClusterVersion(current string, upgrades, versions []string) (string, error) {
+	cur := parseKubernetesVersion(current)
+
+	var candidates []string
+	for _, v := range upgrades {
+		if compareKubernetesVersions(parseKubernetesVersion(v), cur) <= 0 {
+			continue
+		}
+		if len(versions) > 0 && !containsString(versions, v) {
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+
+	if len(candidates) == 0 {
+		return "", ErrNoClusterUpgrade
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		va, vb := parseKubernetesVersion(candidates[a]), parseKubernetesVersion(candidates[b])
+		if minorA, minorB := kubernetesMinor(va), kubernetesMinor(vb); minorA != minorB {
+			return minorA < minorB
+		}
+		return compareKubernetesVersions(va, vb) > 0
+	})
+
+	return candidates[0], nil
+}
+
+// parseKubernetesVersion splits a VKE version such as v1.29.2+1 into its numeric parts
+func parseKubernetesVersion(version string) []int {
+	fields := strings.FieldsFunc(strings.TrimPrefix(version, "v"), func(r rune) bool {
+		return r == '.' || r == '+' || r == '-'
+	})
+
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+
+	return parts
+}
+
+func compareKubernetesVersions(a, b []int) int {
+	for idx := 0; idx < len(a) || idx < len(b); idx++ {
+		var x, y int
+		if idx < len(a) {
+			x = a[idx]
+		}
+		if idx < len(b) {
+			y = b[idx]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// kubernetesMinor returns major and minor combined so minor releases can be compared directly
+func kubernetesMinor(parts []int) int {
+	const minorsPerMajor = 1000
+
+	var major, minor int
+	if len(parts) > 0 {
+		major = parts[0]
+	}
+	if len(parts) > 1 {
+		minor = parts[1]
+	}
+
+	return major*minorsPerMajor + minor
+}
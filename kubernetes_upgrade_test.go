@@ -0,0 +1,336 @@
+package govultr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeUpgradeCluster serves the VKE endpoints used by ClusterUpgrader from memory
+type fakeUpgradeCluster struct {
+	mu       sync.Mutex
+	version  string
+	pending  string
+	nodes    map[string][]Node
+	recycled []string
+	// failRecycle makes the recycle call for the node fail once
+	failRecycle string
+	// slowRecycle keeps recycled nodes listed as active for one more poll
+	slowRecycle bool
+	starting    map[string]bool
+	// overlapped is set when a node is recycled while another one is not active yet
+	overlapped bool
+}
+
+func newFakeUpgradeCluster() *fakeUpgradeCluster {
+	f := &fakeUpgradeCluster{
+		version:  "v1.28.5+1",
+		starting: make(map[string]bool),
+		nodes: map[string][]Node{
+			"pool-a": {
+				{ID: "node-a1", DateCreated: "2024-01-01T00:00:00+00:00", Status: VKEStatusActive},
+				{ID: "node-a2", DateCreated: "2024-01-01T00:00:00+00:00", Status: VKEStatusActive},
+			},
+			"pool-b": {
+				{ID: "node-b1", DateCreated: "2024-01-01T00:00:00+00:00", Status: VKEStatusActive},
+			},
+		},
+	}
+
+	mux.HandleFunc("/v2/kubernetes/versions", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"versions":["v1.30.1+1","v1.29.4+1","v1.29.3+1","v1.28.5+1"]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc", f.cluster)
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/available-upgrades", func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, `{"available_upgrades":["v1.30.1+1","v1.29.3+1","v1.29.4+1","v1.29.5+1"]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/upgrades", f.upgrade)
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools", f.pools)
+	mux.HandleFunc("/v2/kubernetes/clusters/abc/node-pools/", f.poolNodes)
+
+	return f
+}
+
+func (f *fakeUpgradeCluster) cluster(writer http.ResponseWriter, request *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := VKEStatusActive
+	if f.pending != "" {
+		// the control plane reports the new version one poll after the upgrade was requested
+		status, f.version, f.pending = "pending", f.pending, ""
+	}
+	fmt.Fprintf(writer, `{"vke_cluster":{"id":"abc","version":%q,"status":%q}}`, f.version, status)
+}
+
+func (f *fakeUpgradeCluster) upgrade(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "unexpected method "+request.Method, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body ClusterUpgradeReq
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.pending = body.UpgradeVersion
+	f.mu.Unlock()
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeUpgradeCluster) pools(writer http.ResponseWriter, request *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var pools []NodePool
+	for _, id := range []string{"pool-a", "pool-b"} {
+		pools = append(pools, NodePool{ID: id, NodeQuantity: len(f.nodes[id]), Nodes: f.nodes[id]})
+	}
+	_ = json.NewEncoder(writer).Encode(&vkeNodePoolsBase{NodePools: pools, Meta: &Meta{Total: len(pools), Links: &Links{}}})
+}
+
+func (f *fakeUpgradeCluster) poolNodes(writer http.ResponseWriter, request *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// node-pools/{pool}/nodes or node-pools/{pool}/nodes/{node}/recycle
+	parts := strings.Split(strings.TrimPrefix(request.URL.Path, "/v2/kubernetes/clusters/abc/node-pools/"), "/")
+	pool := parts[0]
+
+	if len(parts) == 4 && parts[3] == "recycle" {
+		f.recycle(writer, pool, parts[2])
+		return
+	}
+
+	nodes := f.nodes[pool]
+	_ = json.NewEncoder(writer).Encode(&vkeWorkerNodesBase{WorkerNodes: nodes, Meta: &Meta{Total: len(nodes), Links: &Links{}}})
+
+	// nodes that were being recycled are replaced by a new node on the following poll
+	for idx := range nodes {
+		if nodes[idx].Status != VKEStatusActive {
+			nodes[idx] = Node{ID: nodes[idx].ID + "-new", DateCreated: "2024-06-01T00:00:00+00:00", Status: VKEStatusActive}
+		} else if f.starting[nodes[idx].ID] {
+			nodes[idx].Status = "recycling"
+			delete(f.starting, nodes[idx].ID)
+		}
+	}
+}
+
+func (f *fakeUpgradeCluster) recycle(writer http.ResponseWriter, pool, node string) {
+	if f.failRecycle == node {
+		f.failRecycle = ""
+		http.Error(writer, `{"error":"recycle failed"}`, http.StatusBadRequest)
+		return
+	}
+
+	f.recycled = append(f.recycled, node)
+	for _, nodes := range f.nodes {
+		for idx := range nodes {
+			if nodes[idx].Status != VKEStatusActive || f.starting[nodes[idx].ID] {
+				f.overlapped = true
+			}
+		}
+	}
+
+	for idx := range f.nodes[pool] {
+		if f.nodes[pool][idx].ID != node {
+			continue
+		}
+		if f.slowRecycle {
+			f.starting[node] = true
+		} else {
+			f.nodes[pool][idx].Status = "recycling"
+		}
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func TestClusterUpgrader_Run(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeUpgradeCluster()
+
+	var started []string
+	upgrader := NewClusterUpgrader(client, "abc")
+	upgrader.Wait = &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+	upgrader.OnProgress = func(step ClusterUpgradeStep) {
+		if step.Finished.IsZero() {
+			started = append(started, fmt.Sprintf("%d/%d %s %s", step.Index, step.Total, step.Phase, step.NodeID))
+		}
+	}
+
+	report, err := upgrader.Run(ctx)
+	if err != nil {
+		t.Fatalf("ClusterUpgrader.Run returned %+v", err)
+	}
+
+	if report.FromVersion != "v1.28.5+1" || report.ToVersion != "v1.29.4+1" || fake.version != "v1.29.4+1" {
+		t.Errorf("ClusterUpgrader.Run upgraded %s -> %s, cluster now on %s", report.FromVersion, report.ToVersion, fake.version)
+	}
+
+	expectedStarted := []string{"1/4 control-plane ", "2/4 node node-a1", "3/4 node node-a2", "4/4 node node-b1"}
+	if !reflect.DeepEqual(started, expectedStarted) {
+		t.Errorf("ClusterUpgrader.Run progress = %v, expected %v", started, expectedStarted)
+	}
+
+	if !reflect.DeepEqual(fake.recycled, []string{"node-a1", "node-a2", "node-b1"}) {
+		t.Errorf("ClusterUpgrader.Run recycled %v", fake.recycled)
+	}
+
+	if len(report.Steps) != 4 || !strings.Contains(report.String(), "[4/4] recycle node node-b1 in pool pool-b: done") {
+		t.Errorf("ClusterUpgrader.Run report:\n%s", report)
+	}
+}
+
+func TestClusterUpgrader_Resume(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeUpgradeCluster()
+	fake.failRecycle = "node-a2"
+
+	upgrader := NewClusterUpgrader(client, "abc")
+	upgrader.Version = "v1.29.3+1"
+	upgrader.Wait = &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+	report, err := upgrader.Run(ctx)
+	if err == nil {
+		t.Fatal("ClusterUpgrader.Run expected the recycle error")
+	}
+	if last := report.Steps[len(report.Steps)-1]; last.NodeID != "node-a2" || last.Err == nil {
+		t.Errorf("ClusterUpgrader.Run last step = %+v", last)
+	}
+
+	report, err = upgrader.Run(ctx)
+	if err != nil {
+		t.Fatalf("ClusterUpgrader.Run resume returned %+v", err)
+	}
+
+	// the control plane and node-a1 are not repeated, nor is its replacement recycled
+	if !reflect.DeepEqual(fake.recycled, []string{"node-a1", "node-a2", "node-b1"}) {
+		t.Errorf("ClusterUpgrader.Run recycled %v", fake.recycled)
+	}
+	if len(report.Steps) != 5 || fake.version != "v1.29.3+1" {
+		t.Errorf("ClusterUpgrader.Run report:\n%s", report)
+	}
+}
+
+func TestClusterUpgrader_SlowRecycle(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeUpgradeCluster()
+	fake.slowRecycle = true
+
+	upgrader := NewClusterUpgrader(client, "abc")
+	upgrader.Wait = &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+	if _, err := upgrader.Run(ctx); err != nil {
+		t.Fatalf("ClusterUpgrader.Run returned %+v", err)
+	}
+
+	// the old node is still active on the first poll, which must not end the wait
+	if fake.overlapped {
+		t.Errorf("ClusterUpgrader.Run recycled a node before the previous one was replaced")
+	}
+	if !reflect.DeepEqual(fake.recycled, []string{"node-a1", "node-a2", "node-b1"}) {
+		t.Errorf("ClusterUpgrader.Run recycled %v", fake.recycled)
+	}
+}
+
+func TestClusterUpgrader_Pause(t *testing.T) {
+	setup()
+	defer teardown()
+
+	newFakeUpgradeCluster()
+
+	upgrader := NewClusterUpgrader(client, "abc")
+	upgrader.Wait = &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+	upgrader.OnProgress = func(step ClusterUpgradeStep) {
+		if step.NodeID == "node-a1" && !step.Finished.IsZero() {
+			upgrader.Pause()
+		}
+	}
+
+	pausedCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	report, err := upgrader.Run(pausedCtx)
+	if !errors.Is(err, context.DeadlineExceeded) || !upgrader.Paused() {
+		t.Fatalf("ClusterUpgrader.Run returned %+v while paused", err)
+	}
+	if len(report.Steps) != 2 {
+		t.Errorf("ClusterUpgrader.Run ran %d steps before pausing, expected 2", len(report.Steps))
+	}
+
+	upgrader.OnProgress = nil
+	upgrader.Resume()
+
+	report, err = upgrader.Run(ctx)
+	if err != nil {
+		t.Fatalf("ClusterUpgrader.Run returned %+v after resume", err)
+	}
+	if len(report.Steps) != 4 {
+		t.Errorf("ClusterUpgrader.Run report:\n%s", report)
+	}
+}
+
+func TestNextClusterVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		upgrades []string
+		versions []string
+		expected string
+		err      error
+	}{
+		{
+			name:     "latest patch of next minor",
+			current:  "v1.28.5+1",
+			upgrades: []string{"v1.30.1+1", "v1.29.3+1", "v1.29.4+1"},
+			expected: "v1.29.4+1",
+		},
+		{
+			name:     "only versions still offered",
+			current:  "v1.28.5+1",
+			upgrades: []string{"v1.30.1+1", "v1.29.3+1", "v1.29.4+1"},
+			versions: []string{"v1.30.1+1", "v1.29.3+1"},
+			expected: "v1.29.3+1",
+		},
+		{
+			name:     "patch release",
+			current:  "v1.29.3+1",
+			upgrades: []string{"v1.29.4+1", "v1.29.3+1"},
+			expected: "v1.29.4+1",
+		},
+		{
+			name:     "nothing newer",
+			current:  "v1.30.1+1",
+			upgrades: []string{"v1.29.4+1"},
+			err:      ErrNoClusterUpgrade,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := NextClusterVersion(tt.current, tt.upgrades, tt.versions)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("NextClusterVersion returned error %v, expected %v", err, tt.err)
+			}
+			if version != tt.expected {
+				t.Errorf("NextClusterVersion returned %q, expected %q", version, tt.expected)
+			}
+		})
+	}
+}
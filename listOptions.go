@@ -17,4 +17,8 @@ type ListOptions struct {
 	// Query params that can be used on the list snapshots call
 	// https://www.vultr.com/api/#operation/list-snapshots
 	Description string `url:"description,omitempty"`
+
+	// Query params that can be used on the list backups call
+	// https://www.vultr.com/api/#operation/list-backups
+	InstanceID string `url:"instance_id,omitempty"`
 }
@@ -62,24 +62,43 @@ type LoadBalancer struct {
 
 // LoadBalancerReq gives options for creating or updating a load balancer
 type LoadBalancerReq struct {
-	Region             string           `json:"region,omitempty"`
-	Label              string           `json:"label,omitempty"`
-	Instances          []string         `json:"instances,omitempty"`
-	Nodes              int              `json:"nodes,omitempty"`
-	HealthCheck        *HealthCheck     `json:"health_check,omitempty"`
-	StickySessions     *StickySessions  `json:"sticky_session,omitempty"`
-	ForwardingRules    []ForwardingRule `json:"forwarding_rules,omitempty"`
-	SSL                *SSL             `json:"ssl,omitempty"`
-	AutoSSL            *AutoSSL         `json:"auto_ssl,omitempty"`
-	SSLRedirect        *bool            `json:"ssl_redirect,omitempty"`
-	HTTP2              *bool            `json:"http2,omitempty"`
-	HTTP3              *bool            `json:"http3,omitempty"`
-	ProxyProtocol      *bool            `json:"proxy_protocol,omitempty"`
-	BalancingAlgorithm string           `json:"balancing_algorithm,omitempty"`
-	FirewallRules      []LBFirewallRule `json:"firewall_rules,omitempty"`
-	Timeout            int              `json:"timeout,omitempty"`
-	VPC                *string          `json:"vpc,omitempty"`
-	GlobalRegions      []string         `json:"global_regions,omitempty"`
+	Region             string             `json:"region,omitempty"`
+	Label              string             `json:"label,omitempty"`
+	Instances          []string           `json:"instances,omitempty"`
+	Nodes              int                `json:"nodes,omitempty"`
+	HealthCheck        *HealthCheck       `json:"health_check,omitempty"`
+	StickySessions     *StickySessions    `json:"sticky_session,omitempty"`
+	ForwardingRules    []ForwardingRule   `json:"forwarding_rules,omitempty"`
+	SSL                *SSL               `json:"ssl,omitempty"`
+	AutoSSL            *AutoSSL           `json:"auto_ssl,omitempty"`
+	SSLRedirect        *bool              `json:"ssl_redirect,omitempty"`
+	HTTP2              *bool              `json:"http2,omitempty"`
+	HTTP3              *bool              `json:"http3,omitempty"`
+	ProxyProtocol      *bool              `json:"proxy_protocol,omitempty"`
+	BalancingAlgorithm BalancingAlgorithm `json:"balancing_algorithm,omitempty"`
+	FirewallRules      []LBFirewallRule   `json:"firewall_rules,omitempty"`
+	Timeout            int                `json:"timeout,omitempty"`
+	VPC                *string            `json:"vpc,omitempty"`
+	GlobalRegions      []string           `json:"global_regions,omitempty"`
+}
+
+// BalancingAlgorithm is the method a load balancer uses to pick a backend
+type BalancingAlgorithm string
+
+// Balancing algorithms accepted by the API
+const (
+	BalancingAlgorithmRoundRobin BalancingAlgorithm = "roundrobin"
+	BalancingAlgorithmLeastConn  BalancingAlgorithm = "leastconn"
+)
+
+// Valid reports whether the algorithm is one known to this version of govultr
+func (b BalancingAlgorithm) Valid() bool {
+	return b == BalancingAlgorithmRoundRobin || b == BalancingAlgorithmLeastConn
+}
+
+// String implements fmt.Stringer
+func (b BalancingAlgorithm) String() string {
+	return string(b)
 }
 
 // InstanceList represents instances that are attached to your load balancer
@@ -100,12 +119,12 @@ type HealthCheck struct {
 
 // GenericInfo represents generic configuration of your load balancer
 type GenericInfo struct {
-	BalancingAlgorithm string          `json:"balancing_algorithm,omitempty"`
-	Timeout            int             `json:"timeout,omitempty"`
-	SSLRedirect        *bool           `json:"ssl_redirect,omitempty"`
-	StickySessions     *StickySessions `json:"sticky_sessions,omitempty"`
-	ProxyProtocol      *bool           `json:"proxy_protocol,omitempty"`
-	VPC                string          `json:"vpc,omitempty"`
+	BalancingAlgorithm BalancingAlgorithm `json:"balancing_algorithm,omitempty"`
+	Timeout            int                `json:"timeout,omitempty"`
+	SSLRedirect        *bool              `json:"ssl_redirect,omitempty"`
+	StickySessions     *StickySessions    `json:"sticky_sessions,omitempty"`
+	ProxyProtocol      *bool              `json:"proxy_protocol,omitempty"`
+	VPC                string             `json:"vpc,omitempty"`
 }
 
 // StickySessions represents cookie for your load balancer
@@ -3,6 +3,7 @@ package govultr
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -30,6 +31,11 @@ type Log struct {
 	Metadata     LogMetadata `json:"metadata"`
 }
 
+// Time parses Timestamp with ParseTimestamp
+func (l *Log) Time() (time.Time, error) {
+	return ParseTimestamp(l.Timestamp)
+}
+
 // LogMetadata represents a log entry's metadata
 type LogMetadata struct {
 	UserID          string `json:"user_id"`
@@ -55,6 +61,11 @@ type LogsMeta struct {
 	TotalCount      int    `json:"total_count"`
 }
 
+// ContinueAt parses ContinueTime with ParseTimestamp
+func (l *LogsMeta) ContinueAt() (time.Time, error) {
+	return ParseTimestamp(l.ContinueTime)
+}
+
 // LogsOptions represents the query params for the logs list
 type LogsOptions struct {
 	StartTime    string `url:"start_time"`
@@ -64,6 +75,12 @@ type LogsOptions struct {
 	ResourceID   string `url:"resource_id,omitempty"`
 }
 
+// SetTimeRange formats start and end into the StartTime and EndTime query params
+func (l *LogsOptions) SetTimeRange(start, end time.Time) {
+	l.StartTime = FormatTimestamp(start)
+	l.EndTime = FormatTimestamp(end)
+}
+
 // List retrieves logs
 func (l *LogsServiceHandler) List(ctx context.Context, options LogsOptions) ([]Log, *LogsMeta, *http.Response, error) { //nolint:gocritic,lll
 	req, err := l.client.NewRequest(ctx, http.MethodGet, logsPath, nil)
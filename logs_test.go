@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestLogsServiceHandler_List(t *testing.T) {
@@ -58,3 +59,13 @@ func TestLogsServiceHandler_List(t *testing.T) {
 		t.Errorf("Logs.List meta returned %+v, expected %+v", meta, expectedMeta)
 	}
 }
+
+func TestLogsOptions_SetTimeRange(t *testing.T) {
+	var options LogsOptions
+	start := time.Date(2025, 8, 26, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	options.SetTimeRange(start, start.Add(time.Hour))
+
+	if options.StartTime != "2025-08-26T00:00:00Z" || options.EndTime != "2025-08-26T01:00:00Z" {
+		t.Errorf("LogsOptions.SetTimeRange set %s - %s", options.StartTime, options.EndTime)
+	}
+}
@@ -1,5 +1,7 @@
 package govultr
 
+import "net/http"
+
 // Meta represents the available pagination information
 type Meta struct {
 	Total int    `json:"total"`
@@ -11,3 +13,26 @@ type Links struct {
 	Next string `json:"next"`
 	Prev string `json:"prev"`
 }
+
+const listAllPerPage = 100
+
+// listAll walks every page of a cursor paginated list call and returns the
+// combined results
+func listAll[T any](list func(options *ListOptions) ([]T, *Meta, *http.Response, error)) ([]T, error) {
+	var all []T
+	options := &ListOptions{PerPage: listAllPerPage}
+	for {
+		items, meta, _, err := list(options)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+
+		options.Cursor = meta.Links.Next
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -125,6 +126,11 @@ type Organization struct {
 	DateCreated string `json:"date_created"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *Organization) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
 // OrganizationReq represents an organization modification request
 type OrganizationReq struct {
 	Name string `json:"name"`
@@ -149,6 +155,21 @@ type OrganizationInvitation struct {
 	DateExpiration   string                            `json:"expiration_date"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationInvitation) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
+// RespondedAt parses DateResponded with ParseTimestamp
+func (o *OrganizationInvitation) RespondedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateResponded)
+}
+
+// ExpiresAt parses DateExpiration with ParseTimestamp
+func (o *OrganizationInvitation) ExpiresAt() (time.Time, error) {
+	return ParseTimestamp(o.DateExpiration)
+}
+
 type invitationsBase struct {
 	Invitations []OrganizationInvitation `json:"invites"`
 	Meta        *Meta                    `json:"meta"`
@@ -178,6 +199,16 @@ type OrganizationUser struct {
 	DateAssigned   string `json:"assigned_date,omitempty"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationUser) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
+// SuspendedAt parses DateSuspended with ParseTimestamp
+func (o *OrganizationUser) SuspendedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateSuspended)
+}
+
 type organizationUsersBase struct {
 	Users []OrganizationUser `json:"users"`
 	Meta  *Meta              `json:"meta"`
@@ -193,6 +224,11 @@ type OrganizationGroup struct {
 	DateAssigned string             `json:"assigned_date,omitempty"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationGroup) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
 type organizationGroupBase struct {
 	Group *OrganizationGroup `json:"group"`
 }
@@ -225,6 +261,11 @@ type OrganizationRole struct {
 	DateCreated        string               `json:"date_created"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationRole) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
 type organizationRoleBase struct {
 	Role *OrganizationRole `json:"role"`
 }
@@ -288,6 +329,11 @@ type OrganizationPolicy struct {
 	DateCreated  string                     `json:"date_created"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationPolicy) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
 type organizationPoliciesBase struct {
 	Policies []OrganizationPolicy `json:"policies"`
 	Meta     *Meta                `json:"meta"`
@@ -479,6 +525,16 @@ type OrganizationRoleTrust struct {
 	DateCreated string                         `json:"date_created"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (o *OrganizationRoleTrust) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateCreated)
+}
+
+// ExpiresAt parses DateExpires with ParseTimestamp
+func (o *OrganizationRoleTrust) ExpiresAt() (time.Time, error) {
+	return ParseTimestamp(o.DateExpires)
+}
+
 // OrganizationRoleTrustCondition represents a organization role trust condition
 type OrganizationRoleTrustCondition struct {
 	TimeOfDay OrganizationRoleTrustConditionTime `json:"time_of_day"`
@@ -565,6 +621,16 @@ type OrganizationRoleSession struct {
 	DateAssumed       string   `json:"assumed_at"`
 }
 
+// AssumedAt parses DateAssumed with ParseTimestamp
+func (o *OrganizationRoleSession) AssumedAt() (time.Time, error) {
+	return ParseTimestamp(o.DateAssumed)
+}
+
+// ExpiresAt parses DateExpires with ParseTimestamp
+func (o *OrganizationRoleSession) ExpiresAt() (time.Time, error) {
+	return ParseTimestamp(o.DateExpires)
+}
+
 type organizationRoleSessionsBase struct {
 	Sessions []OrganizationRoleSession `json:"sessions"`
 	Meta     *Meta                     `json:"meta"`
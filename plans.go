@@ -10,7 +10,7 @@ import (
 // PlanService is the interface to interact with the Plans endpoints on the Vultr API
 // Link : https://www.vultr.com/api/#tag/plans
 type PlanService interface {
-	List(ctx context.Context, planType string, options *ListOptions) ([]Plan, *Meta, *http.Response, error)
+	List(ctx context.Context, planType PlanType, options *ListOptions) ([]Plan, *Meta, *http.Response, error)
 	ListBareMetal(ctx context.Context, options *ListOptions) ([]BareMetalPlan, *Meta, *http.Response, error)
 }
 
@@ -19,6 +19,34 @@ type PlanServiceHandler struct {
 	client *Client
 }
 
+// PlanType is the plan family used to filter plan listings
+type PlanType string
+
+// Plan types accepted by the API
+const (
+	PlanTypeAll PlanType = "all"
+	PlanTypeVC2 PlanType = "vc2"
+	PlanTypeVDC PlanType = "vdc"
+	PlanTypeVHF PlanType = "vhf"
+	PlanTypeVHP PlanType = "vhp"
+	PlanTypeVOC PlanType = "voc"
+	PlanTypeVCG PlanType = "vcg"
+)
+
+// Valid reports whether the plan type is one known to this version of govultr
+func (p PlanType) Valid() bool {
+	switch p {
+	case PlanTypeAll, PlanTypeVC2, PlanTypeVDC, PlanTypeVHF, PlanTypeVHP, PlanTypeVOC, PlanTypeVCG:
+		return true
+	}
+	return false
+}
+
+// String implements fmt.Stringer
+func (p PlanType) String() string {
+	return string(p)
+}
+
 // BareMetalPlan represents bare metal plans
 type BareMetalPlan struct {
 	ID          string   `json:"id"`
@@ -61,7 +89,7 @@ type bareMetalPlansBase struct {
 
 // List retrieves a list of all active plans.
 // planType is optional - pass an empty string to get all plans
-func (p *PlanServiceHandler) List(ctx context.Context, planType string, options *ListOptions) ([]Plan, *Meta, *http.Response, error) {
+func (p *PlanServiceHandler) List(ctx context.Context, planType PlanType, options *ListOptions) ([]Plan, *Meta, *http.Response, error) {
 	uri := "/v2/plans"
 
 	req, err := p.client.NewRequest(ctx, http.MethodGet, uri, nil)
@@ -75,7 +103,7 @@ func (p *PlanServiceHandler) List(ctx context.Context, planType string, options
 	}
 
 	if planType != "" {
-		newValues.Add("type", planType)
+		newValues.Add("type", planType.String())
 	}
 
 	req.URL.RawQuery = newValues.Encode()
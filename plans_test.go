@@ -95,3 +95,29 @@ func TestPlanServiceHandler_GetBareMetalList(t *testing.T) {
 		t.Errorf("Plan.List  meta returned %+v, expected %+v", meta, expectedMeta)
 	}
 }
+
+func TestPlanType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/plans", func(writer http.ResponseWriter, request *http.Request) {
+		if planType := request.URL.Query().Get("type"); planType != "vhf" {
+			t.Errorf("Plan.List sent type %q, expected vhf", planType)
+		}
+		fmt.Fprint(writer, `{"plans":[],"meta":{"total":0,"links":{}}}`)
+	})
+
+	if _, _, _, err := client.Plan.List(ctx, PlanTypeVHF, nil); err != nil {
+		t.Errorf("Plan.List returned %+v", err)
+	}
+
+	for _, planType := range []PlanType{PlanTypeAll, PlanTypeVC2, PlanTypeVDC, PlanTypeVHF, PlanTypeVHP, PlanTypeVOC, PlanTypeVCG} {
+		if !planType.Valid() {
+			t.Errorf("plan type %q should be valid", planType)
+		}
+	}
+
+	if PlanType("vx1").Valid() {
+		t.Errorf("plan type vx1 should not be valid")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -35,6 +36,11 @@ type Snapshot struct {
 	AppID          int    `json:"app_id"`
 }
 
+// CreatedAt parses DateCreated with ParseTimestamp
+func (s *Snapshot) CreatedAt() (time.Time, error) {
+	return ParseTimestamp(s.DateCreated)
+}
+
 // SnapshotReq struct is used to create snapshots.
 type SnapshotReq struct {
 	InstanceID  string `json:"instance_id,omitempty"`
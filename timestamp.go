@@ -0,0 +1,40 @@
+package govultr
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampLayouts are the formats the API uses for date and time values
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// ParseTimestamp parses a date or time value returned by the API in any of the
+// formats it uses. Values without a zone are interpreted as UTC and an empty value
+// returns the zero time. The time accessors on resource types use it to read their
+// date fields.
+func ParseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse timestamp %q", value)
+}
+
+// FormatTimestamp formats t the way the API expects time values in requests
+func FormatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
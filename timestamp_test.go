@@ -0,0 +1,64 @@
+package govultr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2021-07-13T14:20:16+00:00", expected: time.Date(2021, 7, 13, 14, 20, 16, 0, time.UTC)},
+		{value: "2021-07-13T16:20:16+02:00", expected: time.Date(2021, 7, 13, 14, 20, 16, 0, time.UTC)},
+		{value: "2025-08-26T00:00:07.123Z", expected: time.Date(2025, 8, 26, 0, 0, 7, 123000000, time.UTC)},
+		{value: "2013-12-19 14:45:41", expected: time.Date(2013, 12, 19, 14, 45, 41, 0, time.UTC)},
+		{value: "2024-01-25T09:41:05", expected: time.Date(2024, 1, 25, 9, 41, 5, 0, time.UTC)},
+		{value: "2024-01-25", expected: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)},
+		{value: ""},
+	}
+
+	for _, tt := range tests {
+		ts, err := ParseTimestamp(tt.value)
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q) returned %+v", tt.value, err)
+			continue
+		}
+
+		if !ts.Equal(tt.expected) {
+			t.Errorf("ParseTimestamp(%q) returned %v, expected %v", tt.value, ts, tt.expected)
+		}
+	}
+
+	if _, err := ParseTimestamp("yesterday"); err == nil {
+		t.Errorf("ParseTimestamp expected an error for an invalid value")
+	}
+}
+
+func TestTimestamp_Accessors(t *testing.T) {
+	instance := &Instance{DateCreated: "2013-12-19 14:45:41"}
+	created, err := instance.CreatedAt()
+	if err != nil || !created.Equal(time.Date(2013, 12, 19, 14, 45, 41, 0, time.UTC)) {
+		t.Errorf("Instance.CreatedAt returned %v, %+v", created, err)
+	}
+
+	zone := &CDNZone{}
+	purged, err := zone.LastPurgedAt()
+	if err != nil || !purged.IsZero() {
+		t.Errorf("CDNZone.LastPurgedAt returned %v, %+v, expected the zero time", purged, err)
+	}
+
+	item := &InvoiceItem{StartDate: "2018-03-18T21:57:58+00:00", EndDate: "bad"}
+	if _, err := item.StartsAt(); err != nil {
+		t.Errorf("InvoiceItem.StartsAt returned %+v", err)
+	}
+	if _, err := item.EndsAt(); err == nil {
+		t.Errorf("InvoiceItem.EndsAt expected an error for an invalid value")
+	}
+
+	alert := &DatabaseAlert{Timestamp: "2024-01-25 09:41:05"}
+	if at, err := alert.Time(); err != nil || !at.Equal(time.Date(2024, 1, 25, 9, 41, 5, 0, time.UTC)) {
+		t.Errorf("DatabaseAlert.Time returned %v, %+v", at, err)
+	}
+}
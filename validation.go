@@ -0,0 +1,95 @@
+package govultr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field on a request
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (f *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", f.Field, f.Message)
+}
+
+// ValidationError is returned by the Validate methods on request structs and
+// lists every problem found rather than only the first one
+type ValidationError struct {
+	Request string
+	Fields  []*FieldError
+}
+
+func (v *ValidationError) Error() string {
+	msgs := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", v.Request, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual field errors so they can be matched with errors.As
+func (v *ValidationError) Unwrap() []error {
+	errs := make([]error, len(v.Fields))
+	for i, f := range v.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// validation accumulates field errors for a single request
+type validation struct {
+	request string
+	fields  []*FieldError
+}
+
+func newValidation(request string) *validation {
+	return &validation{request: request}
+}
+
+func (v *validation) addf(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validation) required(field, value string) {
+	if value == "" {
+		v.addf(field, "is required")
+	}
+}
+
+// merge adds the field errors from a nested request under the given prefix
+func (v *validation) merge(prefix string, err error) {
+	if nested, ok := err.(*ValidationError); ok {
+		for _, f := range nested.Fields {
+			v.addf(prefix+"."+f.Field, "%s", f.Message)
+		}
+	} else if err != nil {
+		v.addf(prefix, "%s", err)
+	}
+}
+
+func (v *validation) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Request: v.request, Fields: v.fields}
+}
+
+type validator interface {
+	Validate() error
+}
+
+// SetRequestValidation enables calling Validate on request structs before
+// they are sent so that invalid requests fail without a round trip to the API
+func (c *Client) SetRequestValidation(enabled bool) {
+	c.validateRequests = enabled
+}
+
+func (c *Client) validate(req validator) error {
+	if !c.validateRequests || isNilInterface(req) {
+		return nil
+	}
+	return req.Validate()
+}
@@ -0,0 +1,85 @@
+package govultr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	err := (&InstanceCreateReq{OsID: 362, SnapshotID: "abc"}).Validate()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("InstanceCreateReq.Validate returned %T, expected *ValidationError", err)
+	}
+
+	if len(verr.Fields) != 3 {
+		t.Errorf("InstanceCreateReq.Validate returned %d field errors, expected 3: %v", len(verr.Fields), err)
+	}
+
+	expected := "invalid InstanceCreateReq: region: is required; plan: is required; os_id: cannot be combined with snapshot_id"
+	if err.Error() != expected {
+		t.Errorf("ValidationError.Error returned %q, expected %q", err.Error(), expected)
+	}
+
+	var ferr *FieldError
+	if !errors.As(err, &ferr) || ferr.Field != "region" {
+		t.Errorf("errors.As returned %+v, expected the region field error", ferr)
+	}
+}
+
+func TestClient_SetRequestValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/v2/instances", func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		fmt.Fprint(writer, defaultInstanceListResponse)
+	})
+
+	invalid := &InstanceCreateReq{Plan: "vc2-1c-2gb"}
+
+	if _, _, err := client.Instance.Create(ctx, invalid); err != nil {
+		t.Errorf("Instance.Create returned %+v with validation disabled", err)
+	}
+
+	client.SetRequestValidation(true)
+
+	_, _, err := client.Instance.Create(ctx, invalid)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("Instance.Create returned %+v, expected a validation error", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Instance.Create sent %d requests, expected the invalid request to be rejected locally", calls)
+	}
+}
+
+func assertValidationFields(t *testing.T, err error, fields []string) {
+	t.Helper()
+
+	if len(fields) == 0 {
+		if err != nil {
+			t.Errorf("Validate returned %+v, expected nil", err)
+		}
+		return
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate returned %+v, expected a *ValidationError", err)
+	}
+
+	got := make([]string, len(verr.Fields))
+	for i, f := range verr.Fields {
+		got[i] = f.Field
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(fields) {
+		t.Errorf("Validate returned fields %v, expected %v (%v)", got, fields, err)
+	}
+}
@@ -0,0 +1,59 @@
+package govultr
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInterval = 10 * time.Second
+	defaultWaitTimeout  = 30 * time.Minute
+)
+
+// WaitOptions controls how helpers poll the API while waiting for a resource
+// to reach a desired state. Zero values fall back to the defaults.
+type WaitOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+func (w *WaitOptions) interval() time.Duration {
+	if w == nil || w.Interval <= 0 {
+		return defaultWaitInterval
+	}
+	return w.Interval
+}
+
+func (w *WaitOptions) timeout() time.Duration {
+	if w == nil || w.Timeout <= 0 {
+		return defaultWaitTimeout
+	}
+	return w.Timeout
+}
+
+// waitFor calls check on every interval until it reports done, returns an
+// error, or the timeout elapses
+func waitFor(ctx context.Context, opts *WaitOptions, desc string, check func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout())
+	defer cancel()
+
+	ticker := time.NewTicker(opts.interval())
+	defer ticker.Stop()
+
+	for {
+		done, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s: %w", desc, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
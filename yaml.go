@@ -0,0 +1,206 @@
+package govultr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const yamlIndent = 2
+
+// marshalYAML encodes v as YAML using its JSON field names
+func marshalYAML(v interface{}) ([]byte, error) {
+	doc, err := jsonNode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(yamlIndent)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// jsonNode converts v into a YAML document node. JSON is valid YAML, so the node is
+// parsed from the JSON encoding to use its field names, and the flow style of JSON is
+// cleared so the entries are written as blocks.
+func jsonNode(v interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	clearNodeStyle(doc)
+	return doc, nil
+}
+
+func clearNodeStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearNodeStyle(child)
+	}
+}
+
+// unmarshalYAML decodes a YAML document into v through its JSON representation, so
+// the json struct tags apply and plain scalars such as "port: 22" decode into string fields
+func unmarshalYAML(data []byte, v interface{}) error {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return err
+	}
+
+	value, err := yamlJSONValue(doc, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+// yamlJSONValue converts node into a value json.Marshal can encode. The type the node
+// decodes into, when known, decides whether a scalar is kept as its string form.
+func yamlJSONValue(node *yaml.Node, t reflect.Type) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		t = nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlJSONValue(node.Content[0], t)
+	case yaml.AliasNode:
+		return yamlJSONValue(node.Alias, t)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			for key.Kind == yaml.AliasNode {
+				key = key.Alias
+			}
+			if key.Kind != yaml.ScalarNode {
+				return nil, errors.New("yaml: mapping keys must be scalars")
+			}
+
+			value, err := yamlJSONValue(node.Content[i+1], yamlFieldType(t, key.Value))
+			if err != nil {
+				return nil, err
+			}
+			m[key.Value] = value
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+
+		s := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := yamlJSONValue(child, elem)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, value)
+		}
+		return s, nil
+	default:
+		if t != nil && t.Kind() == reflect.String && node.ShortTag() != "!!null" {
+			return node.Value, nil
+		}
+
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// yamlFieldType returns the type a mapping entry named key decodes into, matching
+// struct fields the way encoding/json does
+func yamlFieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" {
+				if inner := yamlFieldType(indirectType(field.Type), key); inner != nil {
+					return inner
+				}
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if strings.EqualFold(name, key) {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isYAMLDocument(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// readDocument reads a JSON or YAML document into v
+func readDocument(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.New("empty document")
+	}
+
+	if isYAMLDocument(data) {
+		return unmarshalYAML(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,103 @@
+package govultr
+
+import (
+	"reflect"
+	"testing"
+)
+
+type yamlTestDoc struct {
+	Name    string            `json:"name"`
+	Count   int               `json:"count"`
+	Enabled bool              `json:"enabled"`
+	Port    string            `json:"port"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Items   []yamlTestItem    `json:"items"`
+	Tags    []string          `json:"tags"`
+}
+
+type yamlTestItem struct {
+	ID    string        `json:"id"`
+	Inner *yamlTestItem `json:"inner,omitempty"`
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	doc := &yamlTestDoc{
+		Name:    "web: frontend",
+		Count:   3,
+		Enabled: true,
+		Port:    "22",
+		Labels:  map[string]string{"tier": "web", "empty": ""},
+		Items:   []yamlTestItem{{ID: "a", Inner: &yamlTestItem{ID: "b"}}, {ID: "- dash"}},
+		Tags:    []string{},
+	}
+
+	data, err := marshalYAML(doc)
+	if err != nil {
+		t.Fatalf("marshalYAML returned %+v", err)
+	}
+
+	decoded := &yamlTestDoc{}
+	if err := unmarshalYAML(data, decoded); err != nil {
+		t.Fatalf("unmarshalYAML returned %+v", err)
+	}
+	if !reflect.DeepEqual(decoded, doc) {
+		t.Errorf("unmarshalYAML returned %+v, expected %+v", decoded, doc)
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	data := `
+# comment
+---
+name: &name 'it''s'   # trailing comment
+count: 7
+port: 443
+enabled: true
+labels: {tier: web, owner: *name}
+items:
+- id: first
+  inner:
+    id: "nested # not a comment \x41\U0001F600"
+-
+  id: |
+    second
+    line
+tags:
+- one
+- "two, three"
+- >-
+  folded
+  text
+`
+	doc := &yamlTestDoc{}
+	if err := unmarshalYAML([]byte(data), doc); err != nil {
+		t.Fatalf("unmarshalYAML returned %+v", err)
+	}
+
+	expected := &yamlTestDoc{
+		Name:    "it's",
+		Count:   7,
+		Enabled: true,
+		Port:    "443",
+		Labels:  map[string]string{"tier": "web", "owner": "it's"},
+		Items:   []yamlTestItem{{ID: "first", Inner: &yamlTestItem{ID: "nested # not a comment A\U0001F600"}}, {ID: "second\nline\n"}},
+		Tags:    []string{"one", "two, three", "folded text"},
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("unmarshalYAML returned %+v, expected %+v", doc, expected)
+	}
+}
+
+func TestUnmarshalYAML_Errors(t *testing.T) {
+	tests := map[string]string{
+		"tabs":        "name: a\n\tcount: 1\n",
+		"indentation": "name: a\n    count: 1\n",
+		"type":        "tags: {a: b}\n",
+	}
+
+	for name, data := range tests {
+		if err := unmarshalYAML([]byte(data), &yamlTestDoc{}); err == nil {
+			t.Errorf("unmarshalYAML(%s) expected an error", name)
+		}
+	}
+}